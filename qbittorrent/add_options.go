@@ -0,0 +1,170 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type ContentLayout string
+
+const (
+	ContentLayoutOriginal    ContentLayout = "Original"
+	ContentLayoutSubfolder   ContentLayout = "Subfolder"
+	ContentLayoutNoSubfolder ContentLayout = "NoSubfolder"
+)
+
+type StopCondition string
+
+const (
+	StopConditionNone             StopCondition = "None"
+	StopConditionMetadataReceived StopCondition = "MetadataReceived"
+	StopConditionFilesChecked     StopCondition = "FilesChecked"
+)
+
+// AddTorrentOptions are the optional parameters of /api/v2/torrents/add.
+// Zero values and nil pointers are left out so the server applies its own
+// defaults. Limits are in bytes per second, time limits in minutes; for the
+// share limits -2 means "use the global limit" and -1 "no limit".
+type AddTorrentOptions struct {
+	SavePath                 string
+	DownloadPath             string
+	UseDownloadPath          *bool
+	Cookie                   string
+	Category                 string
+	Tags                     []string
+	SkipChecking             bool
+	Stopped                  *bool
+	RootFolder               *bool
+	ContentLayout            ContentLayout
+	Rename                   string
+	UpLimit                  int64
+	DlLimit                  int64
+	RatioLimit               *float64
+	SeedingTimeLimit         *int
+	InactiveSeedingTimeLimit *int
+	AutoTMM                  *bool
+	SequentialDownload       bool
+	FirstLastPiecePrio       bool
+	StopCondition            StopCondition
+}
+
+// Ptr returns a pointer to v, for filling in optional fields.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// needsVersion reports whether values depends on the server version.
+func (o *AddTorrentOptions) needsVersion() bool {
+	return o != nil && (o.Stopped != nil || o.RootFolder != nil || o.ContentLayout != "")
+}
+
+// values encodes the options for a server with the given Web API version,
+// which selects the qBittorrent 5 name of the "paused" parameter and whether
+// RootFolder and ContentLayout are sent as contentLayout or root_folder.
+func (o *AddTorrentOptions) values(api Version) (url.Values, error) {
+	data := url.Values{}
+	if o == nil {
+		return data, nil
+	}
+
+	setString := func(key, value string) {
+		if value != "" {
+			data.Set(key, value)
+		}
+	}
+	setBool := func(key string, value *bool) {
+		if value != nil {
+			data.Set(key, strconv.FormatBool(*value))
+		}
+	}
+	setInt := func(key string, value *int) {
+		if value != nil {
+			data.Set(key, strconv.Itoa(*value))
+		}
+	}
+
+	setString("savepath", o.SavePath)
+	setString("downloadPath", o.DownloadPath)
+	setBool("useDownloadPath", o.UseDownloadPath)
+	setString("cookie", o.Cookie)
+	setString("category", o.Category)
+	if len(o.Tags) > 0 {
+		data.Set("tags", strings.Join(o.Tags, ","))
+	}
+	if o.SkipChecking {
+		data.Set("skip_checking", "true")
+	}
+	if api.Compare(apiStopStart) >= 0 {
+		setBool("stopped", o.Stopped)
+	} else {
+		setBool("paused", o.Stopped)
+	}
+	if api.Compare(apiContentLayout) >= 0 {
+		layout, err := o.contentLayout()
+		if err != nil {
+			return nil, err
+		}
+		setString("contentLayout", string(layout))
+	} else {
+		rootFolder, err := o.rootFolder()
+		if err != nil {
+			return nil, err
+		}
+		setBool("root_folder", rootFolder)
+	}
+	setString("rename", o.Rename)
+	if o.UpLimit > 0 {
+		data.Set("upLimit", strconv.FormatInt(o.UpLimit, 10))
+	}
+	if o.DlLimit > 0 {
+		data.Set("dlLimit", strconv.FormatInt(o.DlLimit, 10))
+	}
+	if o.RatioLimit != nil {
+		data.Set("ratioLimit", strconv.FormatFloat(*o.RatioLimit, 'f', -1, 64))
+	}
+	setInt("seedingTimeLimit", o.SeedingTimeLimit)
+	setInt("inactiveSeedingTimeLimit", o.InactiveSeedingTimeLimit)
+	setBool("autoTMM", o.AutoTMM)
+	if o.SequentialDownload {
+		data.Set("sequentialDownload", "true")
+	}
+	if o.FirstLastPiecePrio {
+		data.Set("firstLastPiecePrio", "true")
+	}
+	setString("stopCondition", string(o.StopCondition))
+	return data, nil
+}
+
+// contentLayout combines RootFolder and ContentLayout for servers that
+// replaced root_folder with contentLayout.
+func (o *AddTorrentOptions) contentLayout() (ContentLayout, error) {
+	if o.RootFolder == nil {
+		return o.ContentLayout, nil
+	}
+	layout := ContentLayoutNoSubfolder
+	if *o.RootFolder {
+		layout = ContentLayoutSubfolder
+	}
+	if o.ContentLayout != "" && o.ContentLayout != layout {
+		return "", fmt.Errorf("%w: RootFolder %t conflicts with content layout %s", ErrUnsupported, *o.RootFolder, o.ContentLayout)
+	}
+	return layout, nil
+}
+
+// rootFolder combines RootFolder and ContentLayout for servers that only
+// know root_folder. The Original layout has no root_folder equivalent.
+func (o *AddTorrentOptions) rootFolder() (*bool, error) {
+	switch o.ContentLayout {
+	case "":
+		return o.RootFolder, nil
+	case ContentLayoutSubfolder, ContentLayoutNoSubfolder:
+		rootFolder := o.ContentLayout == ContentLayoutSubfolder
+		if o.RootFolder != nil && *o.RootFolder != rootFolder {
+			return nil, fmt.Errorf("%w: RootFolder %t conflicts with content layout %s", ErrUnsupported, *o.RootFolder, o.ContentLayout)
+		}
+		return &rootFolder, nil
+	}
+	return nil, fmt.Errorf("%w: content layout %s requires Web API %s", ErrUnsupported, o.ContentLayout, apiContentLayout)
+}
@@ -0,0 +1,52 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// BuildInfo lists the versions of the libraries qBittorrent was built with.
+// Platform is empty on servers that do not report it.
+type BuildInfo struct {
+	Qt         string `json:"qt"`
+	Libtorrent string `json:"libtorrent"`
+	Boost      string `json:"boost"`
+	OpenSSL    string `json:"openssl"`
+	Zlib       string `json:"zlib"`
+	Bitness    int    `json:"bitness"`
+	Platform   string `json:"platform"`
+}
+
+func (q *QBittorrentClient) GetBuildInfo() (*BuildInfo, error) {
+	return q.GetBuildInfoContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetBuildInfoContext(ctx context.Context) (*BuildInfo, error) {
+	if err := q.requireAPI(ctx, apiBuildInfo, "buildInfo"); err != nil {
+		return nil, err
+	}
+	return q.getBuildInfo(ctx)
+}
+
+// getBuildInfo skips the version check so that ServerVersion can use it.
+func (q *QBittorrentClient) getBuildInfo(ctx context.Context) (*BuildInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/buildInfo", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := q.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var info BuildInfo
+	err = json.NewDecoder(resp.Body).Decode(&info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
@@ -0,0 +1,22 @@
+package qbittorrent
+
+import (
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestGetBuildInfo(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.SetVersion("v4.3.1", "2.6.2")
+
+	client := newTestClient(t, server)
+
+	info, err := client.GetBuildInfo()
+	if err != nil {
+		t.Fatalf("GetBuildInfo failed on Web API 2.6.2: %v", err)
+	}
+	if info.Libtorrent != "2.0.10.0" || info.Bitness != 64 || info.Platform != "linux" {
+		t.Errorf("unexpected build info: %+v", info)
+	}
+}
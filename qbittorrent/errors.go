@@ -0,0 +1,77 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrBadRequest           = errors.New("qbittorrent: bad request")
+	ErrUnauthorized         = errors.New("qbittorrent: unauthorized")
+	ErrNotFound             = errors.New("qbittorrent: not found")
+	ErrConflict             = errors.New("qbittorrent: conflict")
+	ErrUnsupportedMediaType = errors.New("qbittorrent: unsupported media type")
+
+	// ErrAddTorrentFailed is returned when the server accepts an add request
+	// but answers "Fails." because none of the torrents could be added.
+	ErrAddTorrentFailed = errors.New("qbittorrent: failed to add torrent")
+
+	// ErrUnsupported is returned for features the server's Web API version
+	// lacks. It also matches errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("qbittorrent: %w", errors.ErrUnsupported)
+)
+
+// maxErrorBody bounds how much of an error response is kept in APIError.
+const maxErrorBody = 4 << 10
+
+// APIError is returned when the server answers with a status other than
+// 200 OK. It matches the sentinel errors above with errors.Is.
+type APIError struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s failed with status code: %d", e.Method, e.Endpoint, e.StatusCode)
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrUnsupportedMediaType:
+		return e.StatusCode == http.StatusUnsupportedMediaType
+	}
+	return false
+}
+
+// checkResponse turns a non-200 response into an APIError, closing its body.
+func checkResponse(req *http.Request, resp *http.Response) (*http.Response, error) {
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	return nil, &APIError{
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+		StatusCode: resp.StatusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
@@ -0,0 +1,48 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/createCategory":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("Category already exists\n"))
+		case "/api/v2/app/defaultSavePath":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	err = client.AddNewCategory("movies", "/data")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Method != "POST" || apiErr.Endpoint != "/api/v2/torrents/createCategory" ||
+		apiErr.StatusCode != http.StatusConflict || apiErr.Body != "Category already exists" {
+		t.Errorf("unexpected error fields: %+v", apiErr)
+	}
+	if !errors.Is(err, ErrConflict) || errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is mismatch for %v", err)
+	}
+
+	if _, err := client.GetDefaultSavePath(); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound from a getter, got %v", err)
+	}
+	if _, err := client.GetTorrentList(nil); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package qbittorrent
+
+import (
+	"context"
+	"slices"
+	"sort"
+)
+
+type EventType int
+
+const (
+	TorrentAdded EventType = iota + 1
+	TorrentRemoved
+	StateChanged
+	ProgressChanged
+	Completed
+	CategoryChanged
+	TagsChanged
+)
+
+func (t EventType) String() string {
+	switch t {
+	case TorrentAdded:
+		return "TorrentAdded"
+	case TorrentRemoved:
+		return "TorrentRemoved"
+	case StateChanged:
+		return "StateChanged"
+	case ProgressChanged:
+		return "ProgressChanged"
+	case Completed:
+		return "Completed"
+	case CategoryChanged:
+		return "CategoryChanged"
+	case TagsChanged:
+		return "TagsChanged"
+	}
+	return "Unknown"
+}
+
+// Event describes a change to a single torrent between two syncs. Torrent is
+// the state after the change (the last known state for TorrentRemoved) and
+// Previous the state before it (zero for TorrentAdded).
+type Event struct {
+	Type     EventType
+	Hash     string
+	Torrent  Torrent
+	Previous Torrent
+}
+
+type subscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe registers fn to be called with every event produced by Sync, in
+// the goroutine that called Sync. The first sync of a Syncer reports every
+// existing torrent as TorrentAdded; sync once before subscribing to skip them.
+// fn must not call back into Sync or Subscribe.
+func (s *Syncer) Subscribe(fn func(Event)) (unsubscribe func()) {
+	s.subMu.Lock()
+	s.nextSubscriberID++
+	id := s.nextSubscriberID
+	s.subscribers = append(s.subscribers, subscriber{id: id, fn: fn})
+	s.subMu.Unlock()
+
+	return func() {
+		s.subMu.Lock()
+		defer s.subMu.Unlock()
+		s.subscribers = slices.DeleteFunc(s.subscribers, func(sub subscriber) bool {
+			return sub.id == id
+		})
+	}
+}
+
+// Events delivers events on a channel until ctx is done, at which point the
+// channel is closed. Polling is driven separately by Sync or Run; a full
+// channel blocks Sync until the reader catches up.
+func (s *Syncer) Events(ctx context.Context, buffer int) <-chan Event {
+	ch := make(chan Event, buffer)
+	unsubscribe := s.Subscribe(func(e Event) {
+		select {
+		case ch <- e:
+		case <-ctx.Done():
+		}
+	})
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+		close(ch)
+	}()
+
+	return ch
+}
+
+func (s *Syncer) dispatch(events []Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+
+	for _, e := range events {
+		for _, sub := range s.subscribers {
+			sub.fn(e)
+		}
+	}
+}
+
+// diffTorrents describes how the updated and removed torrents of a sync
+// differ from their previous state.
+func diffTorrents(previous, updated map[string]Torrent, removed []string) []Event {
+	var events []Event
+
+	for hash, torrent := range updated {
+		old, ok := previous[hash]
+		if !ok {
+			events = append(events, Event{Type: TorrentAdded, Hash: hash, Torrent: torrent})
+			continue
+		}
+
+		if old.State != torrent.State {
+			events = append(events, Event{Type: StateChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if old.Progress != torrent.Progress {
+			events = append(events, Event{Type: ProgressChanged, Hash: hash, Torrent: torrent, Previous: old})
+			if old.Progress < 1 && torrent.Progress >= 1 {
+				events = append(events, Event{Type: Completed, Hash: hash, Torrent: torrent, Previous: old})
+			}
+		}
+		if old.Category != torrent.Category {
+			events = append(events, Event{Type: CategoryChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if !slices.Equal(old.Tags, torrent.Tags) {
+			events = append(events, Event{Type: TagsChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+	}
+
+	for _, hash := range removed {
+		if old, ok := previous[hash]; ok {
+			events = append(events, Event{Type: TorrentRemoved, Hash: hash, Torrent: old, Previous: old})
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Hash < events[j].Hash
+	})
+	return events
+}
@@ -0,0 +1,73 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSyncerEvents(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,"torrents":{
+			"aaa":{"name":"one","state":"downloading","progress":0.5},
+			"bbb":{"name":"two","state":"stalledUP","progress":1,"tags":"a"},
+			"ddd":{"name":"four","state":"pausedUP","progress":1}}}`,
+		"1": `{"rid":2,"torrents":{
+			"aaa":{"state":"uploading","progress":1,"category":"done"},
+			"bbb":{"tags":"a, b"},
+			"ccc":{"name":"three","state":"metaDL"}},
+			"torrents_removed":["ddd"]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(responses[r.URL.Query().Get("rid")]))
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	syncer := NewSyncer(client)
+
+	var initial []EventType
+	unsubscribe := syncer.Subscribe(func(e Event) {
+		initial = append(initial, e.Type)
+	})
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("initial sync failed: %v", err)
+	}
+	unsubscribe()
+	if !reflect.DeepEqual(initial, []EventType{TorrentAdded, TorrentAdded, TorrentAdded}) {
+		t.Errorf("initial events = %v", initial)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := syncer.Events(ctx, 16)
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("incremental sync failed: %v", err)
+	}
+	cancel()
+
+	var got []string
+	for e := range events {
+		got = append(got, e.Hash+":"+e.Type.String())
+		if e.Type == Completed && e.Previous.Progress != 0.5 {
+			t.Errorf("completed event lost previous state: %+v", e.Previous)
+		}
+	}
+
+	want := []string{
+		"aaa:StateChanged",
+		"aaa:ProgressChanged",
+		"aaa:Completed",
+		"aaa:CategoryChanged",
+		"bbb:TagsChanged",
+		"ccc:TorrentAdded",
+		"ddd:TorrentRemoved",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,173 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+type FilePriority int
+
+const (
+	FilePriorityDoNotDownload FilePriority = 0
+	FilePriorityNormal        FilePriority = 1
+	FilePriorityHigh          FilePriority = 6
+	FilePriorityMaximum       FilePriority = 7
+)
+
+func (p FilePriority) String() string {
+	switch p {
+	case FilePriorityDoNotDownload:
+		return "do not download"
+	case FilePriorityNormal:
+		return "normal"
+	case FilePriorityHigh:
+		return "high"
+	case FilePriorityMaximum:
+		return "maximum"
+	}
+	return "FilePriority(" + strconv.Itoa(int(p)) + ")"
+}
+
+// TorrentFile is a file of a torrent as returned by /api/v2/torrents/files.
+// Name is the path relative to the torrent's root, separated by "/".
+// PieceRange holds the first and last piece the file overlaps, inclusive.
+type TorrentFile struct {
+	Index        int          `json:"index"`
+	Name         string       `json:"name"`
+	Size         ByteSize     `json:"size"`
+	Progress     float64      `json:"progress"`
+	Priority     FilePriority `json:"priority"`
+	IsSeed       bool         `json:"is_seed"`
+	PieceRange   [2]int       `json:"piece_range"`
+	Availability float64      `json:"availability"`
+}
+
+// decodeTorrentFiles decodes a file list. Older servers leave out the index,
+// in which case it is the file's position.
+func decodeTorrentFiles(data []byte) ([]TorrentFile, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	files := make([]TorrentFile, len(raw))
+	for i, entry := range raw {
+		aux := struct {
+			*TorrentFile
+			Index *int `json:"index"`
+		}{TorrentFile: &files[i]}
+		if err := json.Unmarshal(entry, &aux); err != nil {
+			return nil, err
+		}
+		files[i].Index = i
+		if aux.Index != nil {
+			files[i].Index = *aux.Index
+		}
+	}
+	return files, nil
+}
+
+// FileMatcher selects files for SetFilePriorityMatching.
+type FileMatcher func(TorrentFile) bool
+
+// MatchGlob matches files using path.Match. Patterns without a "/" are
+// matched against the base name, so "*.nfo" matches nfo files in any
+// directory; patterns with a "/" are matched against the whole name.
+// A malformed pattern returns an error wrapping path.ErrBadPattern.
+func MatchGlob(pattern string) (FileMatcher, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+	return func(f TorrentFile) bool {
+		name := f.Name
+		if !strings.Contains(pattern, "/") {
+			name = path.Base(name)
+		}
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+// MustMatchGlob is like MatchGlob but panics if pattern is malformed.
+func MustMatchGlob(pattern string) FileMatcher {
+	match, err := MatchGlob(pattern)
+	if err != nil {
+		panic("qbittorrent: " + err.Error())
+	}
+	return match
+}
+
+// MatchExtension matches files with any of the given extensions, ignoring
+// case. The leading dot is optional.
+func MatchExtension(extensions ...string) FileMatcher {
+	return func(f TorrentFile) bool {
+		ext := strings.TrimPrefix(path.Ext(f.Name), ".")
+		for _, want := range extensions {
+			if ext != "" && strings.EqualFold(ext, strings.TrimPrefix(want, ".")) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchSmallerThan matches files smaller than size.
+func MatchSmallerThan(size ByteSize) FileMatcher {
+	return func(f TorrentFile) bool {
+		return f.Size < size
+	}
+}
+
+// MatchLargerThan matches files larger than size.
+func MatchLargerThan(size ByteSize) FileMatcher {
+	return func(f TorrentFile) bool {
+		return f.Size > size
+	}
+}
+
+// SetFilePriorityMatching sets priority on every file of the torrent that
+// matches any of matchers, in a single request, and returns the indexes of
+// those files. For example, to skip nfo files and anything under 1 MiB:
+//
+//	client.SetFilePriorityMatching(hash, FilePriorityDoNotDownload,
+//		MatchExtension("nfo"), MatchSmallerThan(MiB))
+func (q *QBittorrentClient) SetFilePriorityMatching(hash string, priority FilePriority, matchers ...FileMatcher) ([]int, error) {
+	return q.SetFilePriorityMatchingContext(context.Background(), hash, priority, matchers...)
+}
+
+func (q *QBittorrentClient) SetFilePriorityMatchingContext(ctx context.Context, hash string, priority FilePriority, matchers ...FileMatcher) ([]int, error) {
+	files, err := q.GetTorrentContentsContext(ctx, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []int
+	for _, file := range files {
+		for _, match := range matchers {
+			if match(file) {
+				indexes = append(indexes, file.Index)
+				break
+			}
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+
+	if err := q.SetFilePriorityContext(ctx, hash, indexes, priority); err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, "|")
+}
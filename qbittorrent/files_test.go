@@ -0,0 +1,84 @@
+package qbittorrent
+
+import (
+	"errors"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestDecodeTorrentFilesWithoutIndex(t *testing.T) {
+	files, err := decodeTorrentFiles([]byte(`[{"name":"a.mkv","size":10,"priority":1},{"name":"b.nfo","size":2,"priority":0}]`))
+	if err != nil {
+		t.Fatalf("decodeTorrentFiles failed: %v", err)
+	}
+	if files[0].Index != 0 || files[1].Index != 1 || files[1].Priority != FilePriorityDoNotDownload {
+		t.Errorf("unexpected files: %+v", files)
+	}
+}
+
+func TestFileMatchers(t *testing.T) {
+	file := TorrentFile{Name: "Show/Extras/Info.NFO", Size: 512 * KiB}
+	tests := []struct {
+		name    string
+		matcher FileMatcher
+		want    bool
+	}{
+		{"base name glob", MustMatchGlob("*.NFO"), true},
+		{"path glob", MustMatchGlob("Show/*/*.NFO"), true},
+		{"path glob mismatch", MustMatchGlob("Show/*.NFO"), false},
+		{"extension", MatchExtension(".mkv", "nfo"), true},
+		{"other extension", MatchExtension("mkv"), false},
+		{"smaller", MatchSmallerThan(MiB), true},
+		{"larger", MatchLargerThan(MiB), false},
+	}
+	for _, tt := range tests {
+		if got := tt.matcher(file); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatchGlobMalformed(t *testing.T) {
+	if _, err := MatchGlob("[.nfo"); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("expected path.ErrBadPattern, got %v", err)
+	}
+}
+
+func TestSetFilePriorityMatching(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "abc", Name: "show", Files: []qbittorrenttest.File{
+		{Index: 0, Name: "show/episode.mkv", Size: 700 * MiB, Priority: 1, PieceRange: [2]int{0, 699}},
+		{Index: 1, Name: "show/info.nfo", Size: 4 * MiB, Priority: 1, PieceRange: [2]int{699, 703}},
+		{Index: 2, Name: "show/sample.txt", Size: 100 * KiB, Priority: 1, PieceRange: [2]int{703, 703}},
+	}})
+
+	client := newTestClient(t, server)
+
+	indexes, err := client.SetFilePriorityMatching("abc", FilePriorityDoNotDownload, MustMatchGlob("*.nfo"), MatchSmallerThan(MiB))
+	if err != nil {
+		t.Fatalf("SetFilePriorityMatching failed: %v", err)
+	}
+	if !reflect.DeepEqual(indexes, []int{1, 2}) {
+		t.Errorf("indexes = %v, want [1 2]", indexes)
+	}
+
+	// The server reads the file indexes from the id form field.
+	if err := client.SetFilePriority("abc", []int{0}, FilePriorityMaximum); err != nil {
+		t.Fatalf("SetFilePriority failed: %v", err)
+	}
+
+	files, err := client.GetTorrentContents("abc", []int{0, 2})
+	if err != nil {
+		t.Fatalf("GetTorrentContents failed: %v", err)
+	}
+	want := []TorrentFile{
+		{Index: 0, Name: "show/episode.mkv", Size: 700 * MiB, Priority: FilePriorityMaximum, PieceRange: [2]int{0, 699}},
+		{Index: 2, Name: "show/sample.txt", Size: 100 * KiB, Priority: FilePriorityDoNotDownload, PieceRange: [2]int{703, 703}},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %+v, want %+v", files, want)
+	}
+}
@@ -0,0 +1,201 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LogSeverity is the type of a main log message. The values are bit flags so
+// they can be combined in LogOptions.
+type LogSeverity int
+
+const (
+	LogNormal   LogSeverity = 1
+	LogInfo     LogSeverity = 2
+	LogWarning  LogSeverity = 4
+	LogCritical LogSeverity = 8
+)
+
+func (s LogSeverity) String() string {
+	switch s {
+	case LogNormal:
+		return "normal"
+	case LogInfo:
+		return "info"
+	case LogWarning:
+		return "warning"
+	case LogCritical:
+		return "critical"
+	}
+	return "LogSeverity(" + strconv.Itoa(int(s)) + ")"
+}
+
+type LogEntry struct {
+	ID       int
+	Message  string
+	Time     time.Time
+	Severity LogSeverity
+}
+
+func (e *LogEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        int         `json:"id"`
+		Message   string      `json:"message"`
+		Timestamp int64       `json:"timestamp"`
+		Type      LogSeverity `json:"type"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*e = LogEntry{
+		ID:       raw.ID,
+		Message:  raw.Message,
+		Time:     logTime(raw.Timestamp),
+		Severity: raw.Type,
+	}
+	return nil
+}
+
+// logTime converts a log timestamp. Current servers send milliseconds, but
+// some versions send seconds; anything below 1e11 (the year 5138 in seconds,
+// 1973 in milliseconds) is taken to be seconds.
+func logTime(ts int64) time.Time {
+	if ts < 1e11 {
+		return time.Unix(ts, 0)
+	}
+	return time.UnixMilli(ts)
+}
+
+// LogOptions filters /api/v2/log/main. A zero Severities returns messages of
+// every severity. Only entries with an ID greater than LastKnownID are
+// returned; nil returns the whole log.
+type LogOptions struct {
+	Severities  LogSeverity
+	LastKnownID *int
+}
+
+func (o *LogOptions) values() url.Values {
+	data := url.Values{}
+	if o == nil {
+		return data
+	}
+	if o.Severities != 0 {
+		for key, severity := range map[string]LogSeverity{
+			"normal":   LogNormal,
+			"info":     LogInfo,
+			"warning":  LogWarning,
+			"critical": LogCritical,
+		} {
+			data.Set(key, strconv.FormatBool(o.Severities&severity != 0))
+		}
+	}
+	if o.LastKnownID != nil {
+		data.Set("last_known_id", strconv.Itoa(*o.LastKnownID))
+	}
+	return data
+}
+
+// TailLog polls the main log every interval and sends each new entry to ch,
+// asking the server only for entries after the last one delivered. It
+// starts after options.LastKnownID, or at the beginning of the log if that
+// is nil, and runs until ctx is done or a request fails. interval must be
+// positive.
+func (q *QBittorrentClient) TailLog(ctx context.Context, options *LogOptions, interval time.Duration, ch chan<- LogEntry) error {
+	current := LogOptions{LastKnownID: Ptr(-1)}
+	if options != nil {
+		current.Severities = options.Severities
+		if options.LastKnownID != nil {
+			current.LastKnownID = Ptr(*options.LastKnownID)
+		}
+	}
+
+	return tail(ctx, interval, *current.LastKnownID, ch,
+		func(ctx context.Context, lastKnownID int) ([]LogEntry, error) {
+			current.LastKnownID = &lastKnownID
+			return q.GetLogContext(ctx, &current)
+		},
+		func(e LogEntry) int { return e.ID },
+	)
+}
+
+// PeerLogEntry is an entry of the peer log. IP is the zero Addr if the
+// server sent an address that cannot be parsed; RawIP always holds the
+// address as sent.
+type PeerLogEntry struct {
+	ID      int
+	IP      netip.Addr
+	RawIP   string
+	Time    time.Time
+	Blocked bool
+	Reason  string
+}
+
+func (e *PeerLogEntry) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ID        int    `json:"id"`
+		IP        string `json:"ip"`
+		Timestamp int64  `json:"timestamp"`
+		Blocked   bool   `json:"blocked"`
+		Reason    string `json:"reason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	// A malformed address should not make the whole log undecodable.
+	ip, _ := netip.ParseAddr(raw.IP)
+	*e = PeerLogEntry{
+		ID:      raw.ID,
+		IP:      ip,
+		RawIP:   raw.IP,
+		Time:    logTime(raw.Timestamp),
+		Blocked: raw.Blocked,
+		Reason:  raw.Reason,
+	}
+	return nil
+}
+
+// TailPeerLog polls the peer log every interval and sends each entry with an
+// ID greater than lastKnownID to ch, advancing lastKnownID as it goes. Pass
+// -1 to start at the beginning of the log. It runs until ctx is done or a
+// request fails. interval must be positive.
+func (q *QBittorrentClient) TailPeerLog(ctx context.Context, lastKnownID int, interval time.Duration, ch chan<- PeerLogEntry) error {
+	return tail(ctx, interval, lastKnownID, ch, q.GetPeerLogContext,
+		func(e PeerLogEntry) int { return e.ID })
+}
+
+// tail implements TailLog and TailPeerLog.
+func tail[T any](ctx context.Context, interval time.Duration, lastKnownID int, ch chan<- T,
+	fetch func(ctx context.Context, lastKnownID int) ([]T, error), id func(T) int) error {
+	if err := checkInterval(interval); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		entries, err := fetch(ctx, lastKnownID)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			select {
+			case ch <- entry:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			lastKnownID = max(lastKnownID, id(entry))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestGetLog(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddLog("qBittorrent started", int(LogNormal))
+	server.AddLog("Listening on 0.0.0.0:6881", int(LogInfo))
+	server.AddLog("Disk is almost full", int(LogWarning))
+	server.AddLog("Could not open file", int(LogCritical))
+
+	client := newTestClient(t, server)
+
+	entries, err := client.GetLog(&LogOptions{Severities: LogWarning | LogCritical})
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Severity != LogWarning || entries[1].Severity != LogCritical {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if since := time.Since(entries[0].Time); since < 0 || since > time.Minute {
+		t.Errorf("timestamp was not decoded as milliseconds: %v", entries[0].Time)
+	}
+
+	entries, err = client.GetLog(&LogOptions{LastKnownID: Ptr(1)})
+	if err != nil {
+		t.Fatalf("GetLog failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[0].Message != "Disk is almost full" {
+		t.Errorf("unexpected entries after id 1: %+v", entries)
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(`{"id":0,"message":"m","timestamp":1700000000,"type":2}`), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Time.Equal(time.Unix(1700000000, 0)) || entry.Severity.String() != "info" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestTailLog(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddLog("old", int(LogNormal))
+
+	client := newTestClient(t, server)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan LogEntry)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.TailLog(ctx, &LogOptions{LastKnownID: Ptr(0)}, 10*time.Millisecond, ch)
+	}()
+
+	server.AddLog("first", int(LogInfo))
+	server.AddLog("second", int(LogWarning))
+	for _, want := range []string{"first", "second"} {
+		select {
+		case entry := <-ch:
+			if entry.Message != want {
+				t.Fatalf("got %q, want %q", entry.Message, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %q", want)
+		}
+	}
+
+	server.AddLog("third", int(LogCritical))
+	select {
+	case entry := <-ch:
+		if entry.Message != "third" {
+			t.Fatalf("expected only the new entry, got %q", entry.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for a new entry")
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTailPeerLog(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddPeerLog("192.0.2.1", true, "IP filter")
+
+	client := newTestClient(t, server)
+
+	entries, err := client.GetPeerLog(-1)
+	if err != nil {
+		t.Fatalf("GetPeerLog failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].IP != netip.MustParseAddr("192.0.2.1") || !entries[0].Blocked || entries[0].Reason != "IP filter" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := make(chan PeerLogEntry)
+	done := make(chan error, 1)
+	go func() {
+		done <- client.TailPeerLog(ctx, entries[0].ID, 10*time.Millisecond, ch)
+	}()
+
+	server.AddPeerLog("2001:db8::1", true, "banned")
+	// A malformed address must not stop the tail.
+	server.AddPeerLog("bogus", true, "banned")
+	server.AddPeerLog("198.51.100.7", false, "")
+	for _, want := range []string{"2001:db8::1", "bogus", "198.51.100.7"} {
+		select {
+		case entry := <-ch:
+			if entry.RawIP != want {
+				t.Fatalf("got %q, want %s", entry.RawIP, want)
+			}
+			if _, err := netip.ParseAddr(want); (err == nil) != entry.IP.IsValid() {
+				t.Errorf("unexpected IP %v for %s", entry.IP, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTailRejectsInvalidInterval(t *testing.T) {
+	client, err := NewDefaultClient("http://127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := client.TailLog(context.Background(), nil, interval, make(chan LogEntry)); err == nil {
+			t.Errorf("TailLog(%v) succeeded, want an error", interval)
+		}
+		if err := client.TailPeerLog(context.Background(), -1, interval, make(chan PeerLogEntry)); err == nil {
+			t.Errorf("TailPeerLog(%v) succeeded, want an error", interval)
+		}
+	}
+}
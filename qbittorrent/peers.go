@@ -0,0 +1,169 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// TorrentPeers is a single /api/v2/sync/torrentPeers response. Like
+// MainData, incremental responses only carry the changed fields of each
+// peer, so peers are kept as raw JSON for the PeerSyncer to merge.
+type TorrentPeers struct {
+	Rid          int                        `json:"rid"`
+	FullUpdate   bool                       `json:"full_update"`
+	ShowFlags    bool                       `json:"show_flags"`
+	Peers        map[string]json.RawMessage `json:"peers"`
+	PeersRemoved []string                   `json:"peers_removed"`
+}
+
+type PeerConnection string
+
+const (
+	ConnectionBT  PeerConnection = "BT"
+	ConnectionUTP PeerConnection = "μTP"
+	ConnectionWeb PeerConnection = "Web"
+)
+
+// Peer is a peer of a torrent. Flags holds the short flag letters shown in
+// the UI and FlagsDesc their explanation; Files lists the files the peer is
+// exchanging, one per line.
+type Peer struct {
+	IP           string         `json:"ip"`
+	Port         int            `json:"port"`
+	Client       string         `json:"client"`
+	PeerIDClient string         `json:"peer_id_client"`
+	Country      string         `json:"country"`
+	CountryCode  string         `json:"country_code"`
+	Connection   PeerConnection `json:"connection"`
+	Flags        string         `json:"flags"`
+	FlagsDesc    string         `json:"flags_desc"`
+	Progress     float64        `json:"progress"`
+	DlSpeed      ByteRate       `json:"dl_speed"`
+	UpSpeed      ByteRate       `json:"up_speed"`
+	Downloaded   ByteSize       `json:"downloaded"`
+	Uploaded     ByteSize       `json:"uploaded"`
+	Relevance    float64        `json:"relevance"`
+	Files        string         `json:"files"`
+}
+
+// PeerSyncer keeps the peer table of one torrent up to date by polling
+// sync/torrentPeers and merging the incremental responses. Peers are keyed
+// by "ip:port" as reported by the server. It is safe for concurrent use.
+type PeerSyncer struct {
+	client *QBittorrentClient
+	hash   string
+
+	// syncMu serializes requests so that rid only moves forward.
+	syncMu sync.Mutex
+
+	mu    sync.RWMutex
+	rid   int
+	peers map[string]Peer
+}
+
+func NewPeerSyncer(client *QBittorrentClient, hash string) *PeerSyncer {
+	return &PeerSyncer{
+		client: client,
+		hash:   hash,
+		peers:  make(map[string]Peer),
+	}
+}
+
+func (s *PeerSyncer) Sync() error {
+	return s.SyncContext(context.Background())
+}
+
+// SyncContext fetches the changes since the last known rid and applies them.
+func (s *PeerSyncer) SyncContext(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	s.mu.RLock()
+	rid := s.rid
+	s.mu.RUnlock()
+
+	data, err := s.client.GetTorrentPeersDataContext(ctx, s.hash, rid)
+	if err != nil {
+		return err
+	}
+	return s.apply(data)
+}
+
+// Run calls SyncContext every interval until ctx is done or a sync fails.
+// interval must be positive.
+func (s *PeerSyncer) Run(ctx context.Context, interval time.Duration) error {
+	if err := checkInterval(interval); err != nil {
+		return err
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SyncContext(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply merges data into the peer table. As in Syncer.apply, everything is
+// decoded before the table is touched.
+func (s *PeerSyncer) apply(data *TorrentPeers) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	peers := s.peers
+	if data.FullUpdate {
+		peers = make(map[string]Peer, len(data.Peers))
+	}
+
+	updated := make(map[string]Peer, len(data.Peers))
+	for key, raw := range data.Peers {
+		peer := peers[key]
+		if err := json.Unmarshal(raw, &peer); err != nil {
+			return err
+		}
+		updated[key] = peer
+	}
+
+	for key, peer := range updated {
+		peers[key] = peer
+	}
+	for _, key := range data.PeersRemoved {
+		delete(peers, key)
+	}
+
+	s.rid = data.Rid
+	s.peers = peers
+	return nil
+}
+
+func (s *PeerSyncer) Rid() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rid
+}
+
+func (s *PeerSyncer) Peer(key string) (Peer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	peer, ok := s.peers[key]
+	return peer, ok
+}
+
+func (s *PeerSyncer) Peers() map[string]Peer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	peers := make(map[string]Peer, len(s.peers))
+	for key, peer := range s.peers {
+		peers[key] = peer
+	}
+	return peers
+}
@@ -0,0 +1,77 @@
+package qbittorrent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestPeerSyncer(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{
+		Hash: hash,
+		Name: "ubuntu.iso",
+		Peers: map[string]qbittorrenttest.Peer{
+			"192.0.2.1:6881":      {IP: "192.0.2.1", Port: 6881, Client: "qBittorrent 4.6.7", Connection: "BT", Progress: 0.25},
+			"[2001:db8::1]:51413": {IP: "2001:db8::1", Port: 51413, Client: "Transmission 4.0.6", Connection: "μTP", Flags: "D"},
+		},
+	})
+
+	client := newTestClient(t, server)
+
+	syncer := NewPeerSyncer(client, hash)
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if n := len(syncer.Peers()); n != 2 {
+		t.Fatalf("expected 2 peers, got %d", n)
+	}
+	peer, ok := syncer.Peer("[2001:db8::1]:51413")
+	if !ok || peer.Client != "Transmission 4.0.6" || peer.Connection != ConnectionUTP || peer.Port != 51413 {
+		t.Fatalf("unexpected peer: %+v", peer)
+	}
+
+	server.UpdateTorrent(hash, func(torrent *qbittorrenttest.Torrent) {
+		p := torrent.Peers["192.0.2.1:6881"]
+		p.Progress = 0.75
+		p.DlSpeed = 1024
+		torrent.Peers["192.0.2.1:6881"] = p
+		delete(torrent.Peers, "[2001:db8::1]:51413")
+	})
+
+	data, err := client.GetTorrentPeersData(hash, syncer.Rid())
+	if err != nil {
+		t.Fatalf("GetTorrentPeersData failed: %v", err)
+	}
+	if data.FullUpdate || len(data.Peers) != 1 || len(data.PeersRemoved) != 1 {
+		t.Errorf("expected an incremental update, got %+v", data)
+	}
+
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	peers := syncer.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("expected the removed peer to be dropped, got %+v", peers)
+	}
+	peer = peers["192.0.2.1:6881"]
+	if peer.Progress != 0.75 || peer.DlSpeed != 1024 || peer.Client != "qBittorrent 4.6.7" || peer.Connection != ConnectionBT {
+		t.Errorf("incremental update was not merged: %+v", peer)
+	}
+}
+
+func TestPeerSyncerRunRejectsInvalidInterval(t *testing.T) {
+	client, err := NewDefaultClient("http://127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := NewPeerSyncer(client, "0123456789abcdef0123456789abcdef01234567").Run(context.Background(), interval); err == nil {
+			t.Errorf("Run(%v) succeeded, want an error", interval)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package qbittorrent
+
+import (
+	"strconv"
+	"strings"
+)
+
+type PieceState int
+
+const (
+	PieceNotDownloaded PieceState = 0
+	PieceDownloading   PieceState = 1
+	PieceDownloaded    PieceState = 2
+)
+
+func (s PieceState) String() string {
+	switch s {
+	case PieceNotDownloaded:
+		return "not downloaded"
+	case PieceDownloading:
+		return "downloading"
+	case PieceDownloaded:
+		return "downloaded"
+	}
+	return "PieceState(" + strconv.Itoa(int(s)) + ")"
+}
+
+// PieceStates is the state of every piece of a torrent, as returned by
+// /api/v2/torrents/pieceStates.
+type PieceStates []PieceState
+
+type PieceCounts struct {
+	NotDownloaded int
+	Downloading   int
+	Downloaded    int
+}
+
+func (p PieceStates) Counts() PieceCounts {
+	var counts PieceCounts
+	for _, state := range p {
+		switch state {
+		case PieceNotDownloaded:
+			counts.NotDownloaded++
+		case PieceDownloading:
+			counts.Downloading++
+		case PieceDownloaded:
+			counts.Downloaded++
+		}
+	}
+	return counts
+}
+
+// PieceRun is a run of consecutive pieces in the same state.
+type PieceRun struct {
+	State  PieceState
+	Start  int
+	Length int
+}
+
+// Runs returns p run-length encoded, in piece order.
+func (p PieceStates) Runs() []PieceRun {
+	var runs []PieceRun
+	for i, state := range p {
+		if n := len(runs); n > 0 && runs[n-1].State == state {
+			runs[n-1].Length++
+			continue
+		}
+		runs = append(runs, PieceRun{State: state, Start: i, Length: 1})
+	}
+	return runs
+}
+
+// Completion returns the fraction of the pieces first to last, inclusive,
+// that have been downloaded. Pieces outside p count as not downloaded.
+func (p PieceStates) Completion(first, last int) float64 {
+	if last < first {
+		return 0
+	}
+	downloaded := 0
+	for i := max(first, 0); i <= last && i < len(p); i++ {
+		if p[i] == PieceDownloaded {
+			downloaded++
+		}
+	}
+	return float64(downloaded) / float64(last-first+1)
+}
+
+// FileCompletion returns the fraction of the pieces overlapping f that have
+// been downloaded. Since a piece at either end of the range can be shared
+// with a neighbouring file, this can differ slightly from f.Progress.
+func (p PieceStates) FileCompletion(f TorrentFile) float64 {
+	return p.Completion(f.PieceRange[0], f.PieceRange[1])
+}
+
+// Bar renders p as a progress bar of width characters. Each character
+// covers an equal share of the pieces and is '#' if all of them are
+// downloaded, '+' if some are downloaded or downloading, and '.' otherwise.
+func (p PieceStates) Bar(width int) string {
+	if width <= 0 || len(p) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		start := i * len(p) / width
+		end := max((i+1)*len(p)/width, start+1)
+		all, some := true, false
+		for _, state := range p[start:end] {
+			if state == PieceDownloaded {
+				some = true
+			} else {
+				all = false
+				if state == PieceDownloading {
+					some = true
+				}
+			}
+		}
+		switch {
+		case all:
+			b.WriteByte('#')
+		case some:
+			b.WriteByte('+')
+		default:
+			b.WriteByte('.')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestPieceStates(t *testing.T) {
+	states := PieceStates{2, 2, 2, 1, 0, 0, 2, 0}
+
+	if got, want := states.Counts(), (PieceCounts{NotDownloaded: 3, Downloading: 1, Downloaded: 4}); got != want {
+		t.Errorf("Counts() = %+v, want %+v", got, want)
+	}
+
+	wantRuns := []PieceRun{
+		{State: PieceDownloaded, Start: 0, Length: 3},
+		{State: PieceDownloading, Start: 3, Length: 1},
+		{State: PieceNotDownloaded, Start: 4, Length: 2},
+		{State: PieceDownloaded, Start: 6, Length: 1},
+		{State: PieceNotDownloaded, Start: 7, Length: 1},
+	}
+	if got := states.Runs(); !reflect.DeepEqual(got, wantRuns) {
+		t.Errorf("Runs() = %+v, want %+v", got, wantRuns)
+	}
+
+	if got := states.FileCompletion(TorrentFile{PieceRange: [2]int{2, 5}}); got != 0.25 {
+		t.Errorf("FileCompletion() = %v, want 0.25", got)
+	}
+	if got := states.Completion(6, 9); got != 0.25 {
+		t.Errorf("Completion past the end = %v, want 0.25", got)
+	}
+
+	for width, want := range map[int]string{8: "###+..#.", 4: "#+.+", 16: "######++....##.."} {
+		if got := states.Bar(width); got != want {
+			t.Errorf("Bar(%d) = %q, want %q", width, got, want)
+		}
+	}
+}
+
+func TestGetTorrentPiecesStates(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "abc", Name: "a", PieceStates: []int{2, 1, 0}})
+
+	client := newTestClient(t, server)
+
+	states, err := client.GetTorrentPiecesStates("abc")
+	if err != nil {
+		t.Fatalf("GetTorrentPiecesStates failed: %v", err)
+	}
+	if !reflect.DeepEqual(states, PieceStates{PieceDownloaded, PieceDownloading, PieceNotDownloaded}) {
+		t.Errorf("unexpected piece states: %v", states)
+	}
+}
@@ -0,0 +1,274 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Preferences mirrors /api/v2/app/preferences. Every field is a pointer so
+// that a Preferences passed to SetApplicationPreferences only carries the
+// settings that should change; nil fields are left untouched on the server.
+// Keys this struct does not know about are kept in Extra and sent back as-is.
+// Use Changes to send only what was modified in preferences read from the
+// server.
+type Preferences struct {
+	Locale                             *string  `json:"locale,omitempty"`
+	CreateSubfolderEnabled             *bool    `json:"create_subfolder_enabled,omitempty"`
+	StartPausedEnabled                 *bool    `json:"start_paused_enabled,omitempty"`
+	AutoDeleteMode                     *int     `json:"auto_delete_mode,omitempty"`
+	PreallocateAll                     *bool    `json:"preallocate_all,omitempty"`
+	IncompleteFilesExt                 *bool    `json:"incomplete_files_ext,omitempty"`
+	AutoTMMEnabled                     *bool    `json:"auto_tmm_enabled,omitempty"`
+	TorrentChangedTMMEnabled           *bool    `json:"torrent_changed_tmm_enabled,omitempty"`
+	SavePathChangedTMMEnabled          *bool    `json:"save_path_changed_tmm_enabled,omitempty"`
+	CategoryChangedTMMEnabled          *bool    `json:"category_changed_tmm_enabled,omitempty"`
+	UseSubcategories                   *bool    `json:"use_subcategories,omitempty"`
+	SavePath                           *string  `json:"save_path,omitempty"`
+	TempPathEnabled                    *bool    `json:"temp_path_enabled,omitempty"`
+	TempPath                           *string  `json:"temp_path,omitempty"`
+	ExportDir                          *string  `json:"export_dir,omitempty"`
+	ExportDirFin                       *string  `json:"export_dir_fin,omitempty"`
+	TorrentContentLayout               *string  `json:"torrent_content_layout,omitempty"`
+	AddToTopOfQueue                    *bool    `json:"add_to_top_of_queue,omitempty"`
+	TorrentStopCondition               *string  `json:"torrent_stop_condition,omitempty"`
+	ExcludedFileNamesEnabled           *bool    `json:"excluded_file_names_enabled,omitempty"`
+	ExcludedFileNames                  *string  `json:"excluded_file_names,omitempty"`
+	MailNotificationEnabled            *bool    `json:"mail_notification_enabled,omitempty"`
+	MailNotificationSender             *string  `json:"mail_notification_sender,omitempty"`
+	MailNotificationEmail              *string  `json:"mail_notification_email,omitempty"`
+	MailNotificationSMTP               *string  `json:"mail_notification_smtp,omitempty"`
+	MailNotificationSSLEnabled         *bool    `json:"mail_notification_ssl_enabled,omitempty"`
+	MailNotificationAuthEnabled        *bool    `json:"mail_notification_auth_enabled,omitempty"`
+	MailNotificationUsername           *string  `json:"mail_notification_username,omitempty"`
+	MailNotificationPassword           *string  `json:"mail_notification_password,omitempty"`
+	AutorunEnabled                     *bool    `json:"autorun_enabled,omitempty"`
+	AutorunProgram                     *string  `json:"autorun_program,omitempty"`
+	QueueingEnabled                    *bool    `json:"queueing_enabled,omitempty"`
+	MaxActiveDownloads                 *int     `json:"max_active_downloads,omitempty"`
+	MaxActiveTorrents                  *int     `json:"max_active_torrents,omitempty"`
+	MaxActiveUploads                   *int     `json:"max_active_uploads,omitempty"`
+	DontCountSlowTorrents              *bool    `json:"dont_count_slow_torrents,omitempty"`
+	SlowTorrentDlRateThreshold         *int     `json:"slow_torrent_dl_rate_threshold,omitempty"`
+	SlowTorrentUlRateThreshold         *int     `json:"slow_torrent_ul_rate_threshold,omitempty"`
+	SlowTorrentInactiveTimer           *int     `json:"slow_torrent_inactive_timer,omitempty"`
+	MaxRatioEnabled                    *bool    `json:"max_ratio_enabled,omitempty"`
+	MaxRatio                           *float64 `json:"max_ratio,omitempty"`
+	MaxRatioAct                        *int     `json:"max_ratio_act,omitempty"`
+	MaxSeedingTimeEnabled              *bool    `json:"max_seeding_time_enabled,omitempty"`
+	MaxSeedingTime                     *int     `json:"max_seeding_time,omitempty"`
+	MaxInactiveSeedingTimeEnabled      *bool    `json:"max_inactive_seeding_time_enabled,omitempty"`
+	MaxInactiveSeedingTime             *int     `json:"max_inactive_seeding_time,omitempty"`
+	ListenPort                         *int     `json:"listen_port,omitempty"`
+	UPnP                               *bool    `json:"upnp,omitempty"`
+	RandomPort                         *bool    `json:"random_port,omitempty"`
+	DlLimit                            *int     `json:"dl_limit,omitempty"`
+	UpLimit                            *int     `json:"up_limit,omitempty"`
+	MaxConnec                          *int     `json:"max_connec,omitempty"`
+	MaxConnecPerTorrent                *int     `json:"max_connec_per_torrent,omitempty"`
+	MaxUploads                         *int     `json:"max_uploads,omitempty"`
+	MaxUploadsPerTorrent               *int     `json:"max_uploads_per_torrent,omitempty"`
+	StopTrackerTimeout                 *int     `json:"stop_tracker_timeout,omitempty"`
+	EnablePieceExtentAffinity          *bool    `json:"enable_piece_extent_affinity,omitempty"`
+	BittorrentProtocol                 *int     `json:"bittorrent_protocol,omitempty"`
+	LimitUTPRate                       *bool    `json:"limit_utp_rate,omitempty"`
+	LimitTCPOverhead                   *bool    `json:"limit_tcp_overhead,omitempty"`
+	LimitLANPeers                      *bool    `json:"limit_lan_peers,omitempty"`
+	AltDlLimit                         *int     `json:"alt_dl_limit,omitempty"`
+	AltUpLimit                         *int     `json:"alt_up_limit,omitempty"`
+	SchedulerEnabled                   *bool    `json:"scheduler_enabled,omitempty"`
+	ScheduleFromHour                   *int     `json:"schedule_from_hour,omitempty"`
+	ScheduleFromMin                    *int     `json:"schedule_from_min,omitempty"`
+	ScheduleToHour                     *int     `json:"schedule_to_hour,omitempty"`
+	ScheduleToMin                      *int     `json:"schedule_to_min,omitempty"`
+	SchedulerDays                      *int     `json:"scheduler_days,omitempty"`
+	DHT                                *bool    `json:"dht,omitempty"`
+	PEX                                *bool    `json:"pex,omitempty"`
+	LSD                                *bool    `json:"lsd,omitempty"`
+	Encryption                         *int     `json:"encryption,omitempty"`
+	AnonymousMode                      *bool    `json:"anonymous_mode,omitempty"`
+	ProxyIP                            *string  `json:"proxy_ip,omitempty"`
+	ProxyPort                          *int     `json:"proxy_port,omitempty"`
+	ProxyPeerConnections               *bool    `json:"proxy_peer_connections,omitempty"`
+	ProxyAuthEnabled                   *bool    `json:"proxy_auth_enabled,omitempty"`
+	ProxyUsername                      *string  `json:"proxy_username,omitempty"`
+	ProxyPassword                      *string  `json:"proxy_password,omitempty"`
+	ProxyTorrentsOnly                  *bool    `json:"proxy_torrents_only,omitempty"`
+	IPFilterEnabled                    *bool    `json:"ip_filter_enabled,omitempty"`
+	IPFilterPath                       *string  `json:"ip_filter_path,omitempty"`
+	IPFilterTrackers                   *bool    `json:"ip_filter_trackers,omitempty"`
+	BannedIPs                          *string  `json:"banned_IPs,omitempty"`
+	WebUIDomainList                    *string  `json:"web_ui_domain_list,omitempty"`
+	WebUIAddress                       *string  `json:"web_ui_address,omitempty"`
+	WebUIPort                          *int     `json:"web_ui_port,omitempty"`
+	WebUIUPnP                          *bool    `json:"web_ui_upnp,omitempty"`
+	WebUIUsername                      *string  `json:"web_ui_username,omitempty"`
+	WebUIPassword                      *string  `json:"web_ui_password,omitempty"`
+	WebUICSRFProtectionEnabled         *bool    `json:"web_ui_csrf_protection_enabled,omitempty"`
+	WebUIClickjackingProtectionEnabled *bool    `json:"web_ui_clickjacking_protection_enabled,omitempty"`
+	WebUISecureCookieEnabled           *bool    `json:"web_ui_secure_cookie_enabled,omitempty"`
+	WebUIMaxAuthFailCount              *int     `json:"web_ui_max_auth_fail_count,omitempty"`
+	WebUIBanDuration                   *int     `json:"web_ui_ban_duration,omitempty"`
+	WebUISessionTimeout                *int     `json:"web_ui_session_timeout,omitempty"`
+	WebUIHostHeaderValidationEnabled   *bool    `json:"web_ui_host_header_validation_enabled,omitempty"`
+	WebUIUseCustomHTTPHeadersEnabled   *bool    `json:"web_ui_use_custom_http_headers_enabled,omitempty"`
+	WebUICustomHTTPHeaders             *string  `json:"web_ui_custom_http_headers,omitempty"`
+	BypassLocalAuth                    *bool    `json:"bypass_local_auth,omitempty"`
+	BypassAuthSubnetWhitelistEnabled   *bool    `json:"bypass_auth_subnet_whitelist_enabled,omitempty"`
+	BypassAuthSubnetWhitelist          *string  `json:"bypass_auth_subnet_whitelist,omitempty"`
+	AlternativeWebUIEnabled            *bool    `json:"alternative_webui_enabled,omitempty"`
+	AlternativeWebUIPath               *string  `json:"alternative_webui_path,omitempty"`
+	UseHTTPS                           *bool    `json:"use_https,omitempty"`
+	WebUIHTTPSKeyPath                  *string  `json:"web_ui_https_key_path,omitempty"`
+	WebUIHTTPSCertPath                 *string  `json:"web_ui_https_cert_path,omitempty"`
+	DynDNSEnabled                      *bool    `json:"dyndns_enabled,omitempty"`
+	DynDNSService                      *int     `json:"dyndns_service,omitempty"`
+	DynDNSUsername                     *string  `json:"dyndns_username,omitempty"`
+	DynDNSPassword                     *string  `json:"dyndns_password,omitempty"`
+	DynDNSDomain                       *string  `json:"dyndns_domain,omitempty"`
+	RSSRefreshInterval                 *int     `json:"rss_refresh_interval,omitempty"`
+	RSSMaxArticlesPerFeed              *int     `json:"rss_max_articles_per_feed,omitempty"`
+	RSSProcessingEnabled               *bool    `json:"rss_processing_enabled,omitempty"`
+	RSSAutoDownloadingEnabled          *bool    `json:"rss_auto_downloading_enabled,omitempty"`
+	RSSDownloadRepackProperEpisodes    *bool    `json:"rss_download_repack_proper_episodes,omitempty"`
+	RSSSmartEpisodeFilters             *string  `json:"rss_smart_episode_filters,omitempty"`
+	AddTrackersEnabled                 *bool    `json:"add_trackers_enabled,omitempty"`
+	AddTrackers                        *string  `json:"add_trackers,omitempty"`
+	AnnounceIP                         *string  `json:"announce_ip,omitempty"`
+	AnnounceToAllTiers                 *bool    `json:"announce_to_all_tiers,omitempty"`
+	AnnounceToAllTrackers              *bool    `json:"announce_to_all_trackers,omitempty"`
+	AsyncIOThreads                     *int     `json:"async_io_threads,omitempty"`
+	CheckingMemoryUse                  *int     `json:"checking_memory_use,omitempty"`
+	CurrentInterfaceAddress            *string  `json:"current_interface_address,omitempty"`
+	CurrentNetworkInterface            *string  `json:"current_network_interface,omitempty"`
+	DiskCache                          *int     `json:"disk_cache,omitempty"`
+	DiskCacheTTL                       *int     `json:"disk_cache_ttl,omitempty"`
+	EmbeddedTrackerPort                *int     `json:"embedded_tracker_port,omitempty"`
+	EnableCoalesceReadWrite            *bool    `json:"enable_coalesce_read_write,omitempty"`
+	EnableEmbeddedTracker              *bool    `json:"enable_embedded_tracker,omitempty"`
+	EnableMultiConnectionsFromSameIP   *bool    `json:"enable_multi_connections_from_same_ip,omitempty"`
+	EnableUploadSuggestions            *bool    `json:"enable_upload_suggestions,omitempty"`
+	FilePoolSize                       *int     `json:"file_pool_size,omitempty"`
+	OutgoingPortsMin                   *int     `json:"outgoing_ports_min,omitempty"`
+	OutgoingPortsMax                   *int     `json:"outgoing_ports_max,omitempty"`
+	RecheckCompletedTorrents           *bool    `json:"recheck_completed_torrents,omitempty"`
+	ResolvePeerCountries               *bool    `json:"resolve_peer_countries,omitempty"`
+	SaveResumeDataInterval             *int     `json:"save_resume_data_interval,omitempty"`
+	SendBufferLowWatermark             *int     `json:"send_buffer_low_watermark,omitempty"`
+	SendBufferWatermark                *int     `json:"send_buffer_watermark,omitempty"`
+	SendBufferWatermarkFactor          *int     `json:"send_buffer_watermark_factor,omitempty"`
+	SocketBacklogSize                  *int     `json:"socket_backlog_size,omitempty"`
+	UploadChokingAlgorithm             *int     `json:"upload_choking_algorithm,omitempty"`
+	UploadSlotsBehavior                *int     `json:"upload_slots_behavior,omitempty"`
+	UPnPLeaseDuration                  *int     `json:"upnp_lease_duration,omitempty"`
+	UTPTCPMixedMode                    *int     `json:"utp_tcp_mixed_mode,omitempty"`
+	PerformanceWarning                 *bool    `json:"performance_warning,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (p Preferences) MarshalJSON() ([]byte, error) {
+	type plain Preferences
+	data, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extra) == 0 {
+		return data, err
+	}
+
+	merged := make(map[string]json.RawMessage, len(p.Extra))
+	for key, value := range p.Extra {
+		merged[key] = value
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+func (p *Preferences) UnmarshalJSON(data []byte) error {
+	type plain Preferences
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	known := preferenceKeys()
+	for key, value := range raw {
+		if known[key] {
+			continue
+		}
+		if p.Extra == nil {
+			p.Extra = make(map[string]json.RawMessage)
+		}
+		p.Extra[key] = value
+	}
+	return nil
+}
+
+// Changes returns the settings of p that differ from base, for passing to
+// SetApplicationPreferences after modifying a copy of the preferences read
+// from the server. Since a copy shares its pointers with the original,
+// change fields by assigning new pointers (e.g. with Ptr) rather than
+// writing through the existing ones. Nil fields of p are never included,
+// and a nil base counts as all settings being unset.
+func (p *Preferences) Changes(base *Preferences) (*Preferences, error) {
+	current, err := preferenceValues(p)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := preferenceValues(base)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]json.RawMessage)
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || !bytes.Equal(old, value) {
+			changed[key] = value
+		}
+	}
+
+	data, err := json.Marshal(changed)
+	if err != nil {
+		return nil, err
+	}
+	var changes Preferences
+	if err := json.Unmarshal(data, &changes); err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+// preferenceValues returns the JSON value of each key p sets.
+func preferenceValues(p *Preferences) (map[string]json.RawMessage, error) {
+	values := make(map[string]json.RawMessage)
+	if p == nil {
+		return values, nil
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// preferenceKeys returns the JSON keys covered by the Preferences fields.
+var preferenceKeys = sync.OnceValue(func() map[string]bool {
+	keys := make(map[string]bool)
+	t := reflect.TypeOf(Preferences{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+})
@@ -0,0 +1,89 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPreferences(t *testing.T) {
+	var posted map[string]json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"save_path":"/downloads","dht":true,"max_ratio":1.5,"future_option":{"a":1}}`))
+		case "/api/v2/app/setPreferences":
+			if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if err := json.Unmarshal([]byte(r.FormValue("json")), &posted); err != nil {
+				t.Errorf("json form field is not valid JSON: %v", err)
+			}
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	preferences, err := client.GetApplicationPreferences()
+	if err != nil {
+		t.Fatalf("GetApplicationPreferences failed: %v", err)
+	}
+	if preferences.SavePath == nil || *preferences.SavePath != "/downloads" ||
+		preferences.DHT == nil || !*preferences.DHT ||
+		preferences.MaxRatio == nil || *preferences.MaxRatio != 1.5 {
+		t.Errorf("unexpected preferences: %+v", preferences)
+	}
+	if preferences.ListenPort != nil {
+		t.Errorf("missing keys should stay nil, got %d", *preferences.ListenPort)
+	}
+	if got := string(preferences.Extra["future_option"]); got != `{"a":1}` {
+		t.Errorf("unknown key not preserved: %q", got)
+	}
+
+	updated := *preferences
+	updated.DHT = Ptr(false)
+	updated.ListenPort = Ptr(6881)
+	changes, err := updated.Changes(preferences)
+	if err != nil {
+		t.Fatalf("Changes failed: %v", err)
+	}
+	if err := client.SetApplicationPreferences(changes); err != nil {
+		t.Fatalf("SetApplicationPreferences failed: %v", err)
+	}
+	want := map[string]json.RawMessage{
+		"dht":         json.RawMessage(`false`),
+		"listen_port": json.RawMessage(`6881`),
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("posted changes %s", posted)
+	}
+
+	posted = nil
+	if err := client.SetApplicationPreferences(&Preferences{}); err != nil || posted != nil {
+		t.Errorf("expected no request for empty preferences, got %s, %v", posted, err)
+	}
+	if err := client.SetApplicationPreferences(nil); err == nil {
+		t.Error("expected an error for nil preferences")
+	}
+
+	err = client.SetApplicationPreferences(&Preferences{
+		DHT:   Ptr(false),
+		Extra: map[string]json.RawMessage{"future_option": json.RawMessage(`{"a":2}`)},
+	})
+	if err != nil {
+		t.Fatalf("SetApplicationPreferences failed: %v", err)
+	}
+	want = map[string]json.RawMessage{
+		"dht":           json.RawMessage(`false`),
+		"future_option": json.RawMessage(`{"a":2}`),
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("posted %s", posted)
+	}
+}
@@ -0,0 +1,138 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// InfoHash holds the BitTorrent v1 (SHA-1) and v2 (SHA-256) info hashes of a
+// torrent. Hybrid torrents have both; v1-only and v2-only torrents leave the
+// other empty.
+type InfoHash struct {
+	V1 string
+	V2 string
+}
+
+// ID returns the hash qBittorrent uses to identify the torrent in the API:
+// the v1 hash, or the v2 hash truncated to 40 characters for v2-only
+// torrents.
+func (h InfoHash) ID() string {
+	if h.V1 != "" || len(h.V2) < 40 {
+		return h.V1
+	}
+	return h.V2[:40]
+}
+
+func (h InfoHash) IsHybrid() bool {
+	return h.V1 != "" && h.V2 != ""
+}
+
+// Matches reports whether hash is the v1 hash, the v2 hash or the ID of the
+// torrent, ignoring case.
+func (h InfoHash) Matches(hash string) bool {
+	if hash == "" {
+		return false
+	}
+	return strings.EqualFold(hash, h.V1) || strings.EqualFold(hash, h.V2) || strings.EqualFold(hash, h.ID())
+}
+
+func (t Torrent) InfoHash() InfoHash {
+	return InfoHash{V1: t.InfohashV1, V2: t.InfohashV2}
+}
+
+// TorrentProperties are the generic properties of a torrent returned by
+// /api/v2/torrents/properties. Dates the server reports as -1 ("never") are
+// zero, and rate limits of -1 ("unlimited") are 0.
+type TorrentProperties struct {
+	Hash                   string   `json:"hash"`
+	InfoHash               InfoHash `json:"-"`
+	Name                   string   `json:"name"`
+	SavePath               string   `json:"save_path"`
+	DownloadPath           string   `json:"download_path"`
+	Comment                string   `json:"comment"`
+	CreatedBy              string   `json:"created_by"`
+	IsPrivate              bool     `json:"-"`
+	PieceSize              ByteSize `json:"piece_size"`
+	PiecesHave             int      `json:"pieces_have"`
+	PiecesNum              int      `json:"pieces_num"`
+	TotalSize              ByteSize `json:"total_size"`
+	TotalWasted            ByteSize `json:"total_wasted"`
+	TotalUploaded          ByteSize `json:"total_uploaded"`
+	TotalUploadedSession   ByteSize `json:"total_uploaded_session"`
+	TotalDownloaded        ByteSize `json:"total_downloaded"`
+	TotalDownloadedSession ByteSize `json:"total_downloaded_session"`
+	UpLimit                ByteRate `json:"-"`
+	DlLimit                ByteRate `json:"-"`
+	UpSpeed                ByteRate `json:"up_speed"`
+	UpSpeedAvg             ByteRate `json:"up_speed_avg"`
+	DlSpeed                ByteRate `json:"dl_speed"`
+	DlSpeedAvg             ByteRate `json:"dl_speed_avg"`
+	NbConnections          int      `json:"nb_connections"`
+	NbConnectionsLimit     int      `json:"nb_connections_limit"`
+	Peers                  int      `json:"peers"`
+	PeersTotal             int      `json:"peers_total"`
+	Seeds                  int      `json:"seeds"`
+	SeedsTotal             int      `json:"seeds_total"`
+	ShareRatio             float64  `json:"share_ratio"`
+	Popularity             float64  `json:"popularity"`
+
+	CreationDate   time.Time `json:"-"`
+	AdditionDate   time.Time `json:"-"`
+	CompletionDate time.Time `json:"-"`
+	LastSeen       time.Time `json:"-"`
+
+	// ETA is InfiniteETA when the torrent is not expected to finish.
+	ETA         time.Duration `json:"-"`
+	TimeElapsed time.Duration `json:"-"`
+	SeedingTime time.Duration `json:"-"`
+	Reannounce  time.Duration `json:"-"`
+}
+
+func (p *TorrentProperties) UnmarshalJSON(data []byte) error {
+	type plain TorrentProperties
+	aux := struct {
+		*plain
+		InfohashV1     string `json:"infohash_v1"`
+		InfohashV2     string `json:"infohash_v2"`
+		IsPrivate      *bool  `json:"is_private"`
+		IsPrivateOld   *bool  `json:"isPrivate"`
+		UpLimit        int64  `json:"up_limit"`
+		DlLimit        int64  `json:"dl_limit"`
+		CreationDate   int64  `json:"creation_date"`
+		AdditionDate   int64  `json:"addition_date"`
+		CompletionDate int64  `json:"completion_date"`
+		LastSeen       int64  `json:"last_seen"`
+		ETA            int64  `json:"eta"`
+		TimeElapsed    int64  `json:"time_elapsed"`
+		SeedingTime    int64  `json:"seeding_time"`
+		Reannounce     int64  `json:"reannounce"`
+	}{plain: (*plain)(p)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	p.InfoHash = InfoHash{V1: aux.InfohashV1, V2: aux.InfohashV2}
+	// Older servers leave out both info hashes and only send the hash.
+	if p.InfoHash == (InfoHash{}) && len(p.Hash) == 40 {
+		p.InfoHash.V1 = p.Hash
+	}
+	// qBittorrent 5 renamed isPrivate to is_private.
+	if aux.IsPrivate != nil {
+		p.IsPrivate = *aux.IsPrivate
+	} else if aux.IsPrivateOld != nil {
+		p.IsPrivate = *aux.IsPrivateOld
+	}
+	p.UpLimit = ByteRate(max(aux.UpLimit, 0))
+	p.DlLimit = ByteRate(max(aux.DlLimit, 0))
+	p.CreationDate = unixTime(aux.CreationDate)
+	p.AdditionDate = unixTime(aux.AdditionDate)
+	p.CompletionDate = unixTime(aux.CompletionDate)
+	p.LastSeen = unixTime(aux.LastSeen)
+	p.ETA = time.Duration(aux.ETA) * time.Second
+	p.TimeElapsed = time.Duration(aux.TimeElapsed) * time.Second
+	p.SeedingTime = time.Duration(aux.SeedingTime) * time.Second
+	p.Reannounce = time.Duration(aux.Reannounce) * time.Second
+	return nil
+}
@@ -0,0 +1,69 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestTorrentProperties(t *testing.T) {
+	var properties TorrentProperties
+	err := json.Unmarshal([]byte(`{
+		"hash":"0123456789abcdef0123456789abcdef01234567",
+		"infohash_v1":"0123456789abcdef0123456789abcdef01234567",
+		"infohash_v2":"fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210",
+		"isPrivate":true,"piece_size":4194304,"up_limit":-1,"dl_limit":1048576,
+		"creation_date":1700000000,"addition_date":1700000100,"completion_date":-1,"last_seen":-1,
+		"eta":8640000,"time_elapsed":3600,"seeding_time":0,"share_ratio":0.5}`), &properties)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !properties.InfoHash.IsHybrid() || properties.InfoHash.ID() != properties.Hash || !properties.IsPrivate {
+		t.Errorf("unexpected hashes: %+v", properties.InfoHash)
+	}
+	if !properties.InfoHash.Matches("FEDCBA9876543210FEDCBA9876543210FEDCBA9876543210FEDCBA9876543210") {
+		t.Error("expected the v2 hash to match")
+	}
+	if properties.PieceSize != 4*MiB || properties.UpLimit != 0 || properties.DlLimit != MiB {
+		t.Errorf("unexpected sizes or limits: %+v", properties)
+	}
+	if !properties.CreationDate.Equal(time.Unix(1700000000, 0)) || !properties.CompletionDate.IsZero() || !properties.LastSeen.IsZero() {
+		t.Errorf("unexpected dates: %v %v %v", properties.CreationDate, properties.CompletionDate, properties.LastSeen)
+	}
+	if properties.ETA != InfiniteETA || properties.TimeElapsed != time.Hour {
+		t.Errorf("unexpected durations: %v %v", properties.ETA, properties.TimeElapsed)
+	}
+
+	v2Only := InfoHash{V2: "fedcba9876543210fedcba9876543210fedcba9876543210fedcba9876543210"}
+	if v2Only.ID() != "fedcba9876543210fedcba9876543210fedcba98" || !v2Only.Matches(v2Only.ID()) {
+		t.Errorf("unexpected v2-only ID %q", v2Only.ID())
+	}
+}
+
+func TestGetTorrentGenericProperties(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: hash, Name: "ubuntu.iso", AddedOn: 1700000000, PieceSize: 2 * MiB})
+
+	client := newTestClient(t, server)
+
+	properties, err := client.GetTorrentGenericProperties(hash)
+	if err != nil {
+		t.Fatalf("GetTorrentGenericProperties failed: %v", err)
+	}
+	if properties.Name != "ubuntu.iso" || properties.InfoHash.V1 != hash || properties.PieceSize != 2*MiB {
+		t.Errorf("unexpected properties: %+v", properties)
+	}
+	if !properties.AdditionDate.Equal(time.Unix(1700000000, 0)) || !properties.CompletionDate.IsZero() || properties.DlLimit != 0 {
+		t.Errorf("sentinels were not handled: %+v", properties)
+	}
+
+	if _, err := client.GetTorrentGenericProperties("ffffffffffffffffffffffffffffffffffffffff"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -2,42 +2,62 @@ package qbittorrent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"strings"
+	"sync"
 )
 
 type QBittorrentClient struct {
-	baseURL    string
-    client     *http.Client
-    cookie     *http.Cookie
+	baseURL string
+	client  *http.Client
+
+	mu          sync.RWMutex
+	cookie      *http.Cookie
+	credentials CredentialsFunc
+	version     *ServerVersion
+
+	// authMu coalesces concurrent re-logins into a single request.
+	authMu sync.Mutex
+	// versionMu coalesces concurrent server version lookups.
+	versionMu sync.Mutex
+	// speedLimitsMu serializes SetAlternativeSpeedLimits on servers without
+	// setSpeedLimitsMode.
+	speedLimitsMu sync.Mutex
 }
 
+// CredentialsFunc supplies the username and password used to log in again
+// after the session has expired.
+type CredentialsFunc func(ctx context.Context) (username, password string, err error)
+
 func NewClient(baseURL string, httpClient *http.Client, cookie *http.Cookie) (*QBittorrentClient, error) {
-    
+
 	if baseURL == "" {
 		return nil, fmt.Errorf("baseURL is empty")
 	}
 
 	if httpClient == nil {
-        httpClient = &http.Client{}
-    }
-    if cookie == nil {
-        cookie = &http.Cookie{}
-    }
-    
-    return &QBittorrentClient{
-        baseURL:    baseURL,
-        client:     httpClient,
-        cookie:     cookie,
-    }, nil
+		httpClient = &http.Client{}
+	}
+	if cookie == nil {
+		cookie = &http.Cookie{}
+	}
+
+	return &QBittorrentClient{
+		baseURL: baseURL,
+		client:  httpClient,
+		cookie:  cookie,
+	}, nil
 }
 
 func NewDefaultClient(baseURL string) (*QBittorrentClient, error) {
-    return NewClient(baseURL, nil, nil)
+	return NewClient(baseURL, nil, nil)
 }
 
 func (q *QBittorrentClient) GetHttpClient() *http.Client {
@@ -48,66 +68,207 @@ func (q *QBittorrentClient) GetHttpClient() *http.Client {
 }
 
 func (q *QBittorrentClient) GetCookie() *http.Cookie {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
 	if q.cookie == nil {
 		return &http.Cookie{}
 	}
 	return q.cookie
 }
 
+// SetCredentials makes the client log in again with the credentials returned
+// by fn whenever the server rejects the session with 403 Forbidden. Login
+// sets this up automatically; pass nil to disable re-authentication.
+func (q *QBittorrentClient) SetCredentials(fn CredentialsFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.credentials = fn
+}
+
 func (q *QBittorrentClient) Login(username, password string) error {
+	return q.LoginContext(context.Background(), username, password)
+}
+
+func (q *QBittorrentClient) LoginContext(ctx context.Context, username, password string) error {
+	q.authMu.Lock()
+	defer q.authMu.Unlock()
+
+	cookie, err := q.login(ctx, username, password)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	q.cookie = cookie
+	q.credentials = func(context.Context) (string, string, error) {
+		return username, password, nil
+	}
+	q.version = nil
+	q.mu.Unlock()
+	return nil
+}
+
+func (q *QBittorrentClient) login(ctx context.Context, username, password string) (*http.Cookie, error) {
 	data := url.Values{}
 	data.Set("username", username)
 	data.Set("password", password)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/auth/login", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/auth/login", strings.NewReader(data.Encode()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := q.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	resp, err = checkResponse(req, resp)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("login failed with status code: %d", resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(string(body)) == "Fails." {
+		return nil, fmt.Errorf("%w: invalid username or password", ErrUnauthorized)
 	}
 
-	q.cookie = resp.Cookies()[0]
-	return nil
+	// The session cookie is named SID on 4.x and QBT_SID_<port> on 5.x.
+	// Servers that bypass authentication for the client may not set one.
+	cookies := resp.Cookies()
+	if len(cookies) == 0 {
+		return &http.Cookie{}, nil
+	}
+	return cookies[0], nil
 }
 
 func (q *QBittorrentClient) Logout() error {
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/auth/logout", nil)
+	return q.LogoutContext(context.Background())
+}
+
+func (q *QBittorrentClient) LogoutContext(ctx context.Context) error {
+	// Forget the credentials first so that an expired session is not
+	// renewed just to be logged out.
+	q.SetCredentials(nil)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/auth/logout", nil)
 	if err != nil {
 		return err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("logout failed with status code: %d", resp.StatusCode)
+	q.mu.Lock()
+	q.cookie = &http.Cookie{}
+	q.mu.Unlock()
+	return nil
+}
+
+// do sends req with the session cookie attached. If the server answers 403
+// Forbidden and credentials are known, it logs in again and replays the
+// request once with the new session. Any status other than 200 OK is
+// returned as an *APIError.
+func (q *QBittorrentClient) do(req *http.Request) (*http.Response, error) {
+	q.mu.RLock()
+	cookie := q.cookie
+	canRetry := q.credentials != nil && (req.Body == nil || req.GetBody != nil)
+	q.mu.RUnlock()
+
+	resp, err := q.client.Do(withCookie(req, cookie))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusForbidden || !canRetry {
+		return checkResponse(req, resp)
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err := q.reauthenticate(req.Context(), cookie); err != nil {
+		return nil, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	q.mu.RLock()
+	cookie = q.cookie
+	q.mu.RUnlock()
+
+	resp, err = q.client.Do(withCookie(retry, cookie))
+	if err != nil {
+		return nil, err
+	}
+	return checkResponse(retry, resp)
+}
+
+// reauthenticate logs in again unless another goroutine already replaced the
+// stale session while this one was waiting.
+func (q *QBittorrentClient) reauthenticate(ctx context.Context, stale *http.Cookie) error {
+	q.authMu.Lock()
+	defer q.authMu.Unlock()
+
+	q.mu.RLock()
+	current := q.cookie
+	credentials := q.credentials
+	q.mu.RUnlock()
+
+	if current != stale {
+		return nil
+	}
+	if credentials == nil {
+		return fmt.Errorf("session expired and no credentials are available")
+	}
+
+	username, password, err := credentials(ctx)
+	if err != nil {
+		return err
+	}
+	cookie, err := q.login(ctx, username, password)
+	if err != nil {
+		return err
 	}
 
-	q.cookie = nil
+	q.mu.Lock()
+	q.cookie = cookie
+	q.version = nil
+	q.mu.Unlock()
 	return nil
 }
 
+func withCookie(req *http.Request, cookie *http.Cookie) *http.Request {
+	req.Header.Del("Cookie")
+	if cookie != nil && cookie.Name != "" {
+		req.AddCookie(cookie)
+	}
+	return req
+}
+
 func (q *QBittorrentClient) GetApplicationVersion() (string, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/app/version", nil)
+	return q.GetApplicationVersionContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetApplicationVersionContext(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/version", nil)
 	if err != nil {
 		return "", err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -122,13 +283,16 @@ func (q *QBittorrentClient) GetApplicationVersion() (string, error) {
 }
 
 func (q *QBittorrentClient) GetAPIVersion() (string, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/app/webapiVersion", nil)
+	return q.GetAPIVersionContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAPIVersionContext(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/webapiVersion", nil)
 	if err != nil {
 		return "", err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -142,63 +306,81 @@ func (q *QBittorrentClient) GetAPIVersion() (string, error) {
 	return string(body), nil
 }
 
+func (q *QBittorrentClient) GetApplicationPreferences() (*Preferences, error) {
+	return q.GetApplicationPreferencesContext(context.Background())
+}
 
-func (q *QBittorrentClient) GetApplicationPreferences() (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/app/preferences", nil)
+func (q *QBittorrentClient) GetApplicationPreferencesContext(ctx context.Context) (*Preferences, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/preferences", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var preferences map[string]interface{}
+	var preferences Preferences
 	err = json.NewDecoder(resp.Body).Decode(&preferences)
 	if err != nil {
 		return nil, err
 	}
 
-	return preferences, nil
+	return &preferences, nil
 }
 
-func (q *QBittorrentClient) SetApplicationPreferences(preferences map[string]interface{}) error {
+func (q *QBittorrentClient) SetApplicationPreferences(preferences *Preferences) error {
+	return q.SetApplicationPreferencesContext(context.Background(), preferences)
+}
+
+// SetApplicationPreferencesContext sends the non-nil fields of preferences
+// (plus Extra) as the "json" form field expected by setPreferences. To send
+// back preferences read with GetApplicationPreferences, pass the result of
+// Preferences.Changes rather than the whole value. Nothing is sent if no
+// field is set.
+func (q *QBittorrentClient) SetApplicationPreferencesContext(ctx context.Context, preferences *Preferences) error {
+	if preferences == nil {
+		return fmt.Errorf("preferences is nil")
+	}
 	jsonData, err := json.Marshal(preferences)
 	if err != nil {
 		return err
 	}
+	if string(jsonData) == "{}" {
+		return nil
+	}
+
+	data := url.Values{}
+	data.Set("json", string(jsonData))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/app/setPreferences", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/app/setPreferences", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.AddCookie(q.cookie)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set preferences failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetDefaultSavePath() (string, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/app/defaultSavePath", nil)
+	return q.GetDefaultSavePathContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetDefaultSavePathContext(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/defaultSavePath", nil)
 	if err != nil {
 		return "", err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return "", err
 	}
@@ -212,20 +394,133 @@ func (q *QBittorrentClient) GetDefaultSavePath() (string, error) {
 	return string(body), nil
 }
 
-func (q *QBittorrentClient) GetLog() ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/log/main", nil)
+// Shutdown stops the qBittorrent daemon. The session is gone afterwards.
+func (q *QBittorrentClient) Shutdown() error {
+	return q.ShutdownContext(context.Background())
+}
+
+func (q *QBittorrentClient) ShutdownContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/app/shutdown", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// NetworkInterface is an interface qBittorrent can bind to. Name is for
+// display; Value is what the current_network_interface preference expects.
+type NetworkInterface struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (q *QBittorrentClient) GetNetworkInterfaces() ([]NetworkInterface, error) {
+	return q.GetNetworkInterfacesContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetNetworkInterfacesContext(ctx context.Context) ([]NetworkInterface, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/networkInterfaceList", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var interfaces []NetworkInterface
+	err = json.NewDecoder(resp.Body).Decode(&interfaces)
+	if err != nil {
+		return nil, err
+	}
+
+	return interfaces, nil
+}
+
+// GetNetworkInterfaceAddresses lists the addresses of the interface with the
+// given Value, or of all interfaces if iface is empty.
+func (q *QBittorrentClient) GetNetworkInterfaceAddresses(iface string) ([]netip.Addr, error) {
+	return q.GetNetworkInterfaceAddressesContext(context.Background(), iface)
+}
+
+func (q *QBittorrentClient) GetNetworkInterfaceAddressesContext(ctx context.Context, iface string) ([]netip.Addr, error) {
+	data := url.Values{}
+	data.Set("iface", iface)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/app/networkInterfaceAddressList?"+data.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := q.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var addresses []netip.Addr
+	err = json.NewDecoder(resp.Body).Decode(&addresses)
 	if err != nil {
 		return nil, err
 	}
+
+	return addresses, nil
+}
+
+// SendTestEmail asks the server to send a test message using the email
+// notification preferences. Servers without the endpoint return
+// ErrUnsupported.
+func (q *QBittorrentClient) SendTestEmail() error {
+	return q.SendTestEmailContext(context.Background())
+}
+
+func (q *QBittorrentClient) SendTestEmailContext(ctx context.Context) error {
+	if err := q.requireAPI(ctx, apiSendTestEmail, "sendTestEmail"); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/app/sendTestEmail", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
 	defer resp.Body.Close()
 
-	var log []map[string]interface{}
+	return nil
+}
+
+func (q *QBittorrentClient) GetLog(options *LogOptions) ([]LogEntry, error) {
+	return q.GetLogContext(context.Background(), options)
+}
+
+func (q *QBittorrentClient) GetLogContext(ctx context.Context, options *LogOptions) ([]LogEntry, error) {
+	data := options.values()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/log/main?"+data.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := q.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var log []LogEntry
 	err = json.NewDecoder(resp.Body).Decode(&log)
 	if err != nil {
 		return nil, err
@@ -234,20 +529,25 @@ func (q *QBittorrentClient) GetLog() ([]map[string]interface{}, error) {
 	return log, nil
 }
 
-func (q *QBittorrentClient) GetPeerLog() ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/log/peers", nil)
+// GetPeerLog returns the peer log entries with an ID greater than
+// lastKnownID; pass -1 for the whole log.
+func (q *QBittorrentClient) GetPeerLog(lastKnownID int) ([]PeerLogEntry, error) {
+	return q.GetPeerLogContext(context.Background(), lastKnownID)
+}
+
+func (q *QBittorrentClient) GetPeerLogContext(ctx context.Context, lastKnownID int) ([]PeerLogEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/log/peers?last_known_id=%d", q.baseURL, lastKnownID), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var peerLog []map[string]interface{}
+	var peerLog []PeerLogEntry
 	err = json.NewDecoder(resp.Body).Decode(&peerLog)
 	if err != nil {
 		return nil, err
@@ -257,81 +557,93 @@ func (q *QBittorrentClient) GetPeerLog() ([]map[string]interface{}, error) {
 }
 
 // Sync
-func (q *QBittorrentClient) GetMainData(rid int) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/sync/maindata?rid=%d", q.baseURL, rid), nil)
+func (q *QBittorrentClient) GetMainData(rid int) (*MainData, error) {
+	return q.GetMainDataContext(context.Background(), rid)
+}
+
+func (q *QBittorrentClient) GetMainDataContext(ctx context.Context, rid int) (*MainData, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/sync/maindata?rid=%d", q.baseURL, rid), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var mainData map[string]interface{}
+	var mainData MainData
 	err = json.NewDecoder(resp.Body).Decode(&mainData)
 	if err != nil {
 		return nil, err
 	}
 
-	return mainData, nil
+	return &mainData, nil
+}
+
+func (q *QBittorrentClient) GetTorrentPeersData(hash string, rid int) (*TorrentPeers, error) {
+	return q.GetTorrentPeersDataContext(context.Background(), hash, rid)
 }
 
-func (q *QBittorrentClient) GetTorrentPeersData(hash string, rid int) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/sync/torrentPeers?hash=%s&rid=%d", q.baseURL, hash, rid), nil)
+func (q *QBittorrentClient) GetTorrentPeersDataContext(ctx context.Context, hash string, rid int) (*TorrentPeers, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/sync/torrentPeers?hash=%s&rid=%d", q.baseURL, hash, rid), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var peersData map[string]interface{}
+	var peersData TorrentPeers
 	err = json.NewDecoder(resp.Body).Decode(&peersData)
 	if err != nil {
 		return nil, err
 	}
 
-	return peersData, nil
+	return &peersData, nil
 }
 
 // Transfer Info
-func (q *QBittorrentClient) GetGlobalTransferInfo() (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/transfer/info", nil)
+func (q *QBittorrentClient) GetGlobalTransferInfo() (*TransferInfo, error) {
+	return q.GetGlobalTransferInfoContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetGlobalTransferInfoContext(ctx context.Context) (*TransferInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/transfer/info", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var transferInfo map[string]interface{}
+	var transferInfo TransferInfo
 	err = json.NewDecoder(resp.Body).Decode(&transferInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	return transferInfo, nil
+	return &transferInfo, nil
 }
 
 func (q *QBittorrentClient) GetAlternativeSpeedLimitsState() (bool, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/transfer/speedLimitsMode", nil)
+	return q.GetAlternativeSpeedLimitsStateContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAlternativeSpeedLimitsStateContext(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/transfer/speedLimitsMode", nil)
 	if err != nil {
 		return false, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return false, err
 	}
@@ -347,33 +659,81 @@ func (q *QBittorrentClient) GetAlternativeSpeedLimitsState() (bool, error) {
 }
 
 func (q *QBittorrentClient) ToggleAlternativeSpeedLimits() error {
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/transfer/toggleSpeedLimitsMode", nil)
+	return q.ToggleAlternativeSpeedLimitsContext(context.Background())
+}
+
+func (q *QBittorrentClient) ToggleAlternativeSpeedLimitsContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/transfer/toggleSpeedLimitsMode", nil)
 	if err != nil {
 		return err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("toggle speed limits failed with status code: %d", resp.StatusCode)
+	return nil
+}
+
+// SetAlternativeSpeedLimits turns the alternative speed limits on or off.
+// Unlike ToggleAlternativeSpeedLimits it is idempotent. Servers older than
+// Web API 2.11.3 have no /transfer/setSpeedLimitsMode and get a read followed
+// by a toggle when the mode differs; callers sharing the client are
+// serialized, but a toggle from another client between the two requests can
+// still flip the mode.
+func (q *QBittorrentClient) SetAlternativeSpeedLimits(enabled bool) error {
+	return q.SetAlternativeSpeedLimitsContext(context.Background(), enabled)
+}
+
+func (q *QBittorrentClient) SetAlternativeSpeedLimitsContext(ctx context.Context, enabled bool) error {
+	version, err := q.ServerVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+	if version.API.Compare(apiSpeedLimitsMode) < 0 {
+		q.speedLimitsMu.Lock()
+		defer q.speedLimitsMu.Unlock()
+
+		current, err := q.GetAlternativeSpeedLimitsStateContext(ctx)
+		if err != nil || current == enabled {
+			return err
+		}
+		return q.ToggleAlternativeSpeedLimitsContext(ctx)
 	}
 
+	data := url.Values{}
+	data.Set("mode", "0")
+	if enabled {
+		data.Set("mode", "1")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/transfer/setSpeedLimitsMode", strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
 	return nil
 }
 
 func (q *QBittorrentClient) GetGlobalDownloadLimit() (int, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/transfer/downloadLimit", nil)
+	return q.GetGlobalDownloadLimitContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetGlobalDownloadLimitContext(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/transfer/downloadLimit", nil)
 	if err != nil {
 		return 0, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -389,37 +749,39 @@ func (q *QBittorrentClient) GetGlobalDownloadLimit() (int, error) {
 }
 
 func (q *QBittorrentClient) SetGlobalDownloadLimit(limit int) error {
+	return q.SetGlobalDownloadLimitContext(context.Background(), limit)
+}
+
+func (q *QBittorrentClient) SetGlobalDownloadLimitContext(ctx context.Context, limit int) error {
 	data := url.Values{}
 	data.Set("limit", fmt.Sprintf("%d", limit))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/transfer/setDownloadLimit", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/transfer/setDownloadLimit", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set download limit failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetGlobalUploadLimit() (int, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/transfer/uploadLimit", nil)
+	return q.GetGlobalUploadLimitContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetGlobalUploadLimitContext(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/transfer/uploadLimit", nil)
 	if err != nil {
 		return 0, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -435,68 +797,133 @@ func (q *QBittorrentClient) GetGlobalUploadLimit() (int, error) {
 }
 
 func (q *QBittorrentClient) SetGlobalUploadLimit(limit int) error {
+	return q.SetGlobalUploadLimitContext(context.Background(), limit)
+}
+
+func (q *QBittorrentClient) SetGlobalUploadLimitContext(ctx context.Context, limit int) error {
 	data := url.Values{}
 	data.Set("limit", fmt.Sprintf("%d", limit))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/transfer/setUploadLimit", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/transfer/setUploadLimit", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set upload limit failed with status code: %d", resp.StatusCode)
+	return nil
+}
+
+// banPeersBatchSize bounds the number of peers sent in one banPeers request
+// to keep the form body at a size any reverse proxy accepts.
+const banPeersBatchSize = 500
+
+// BanPeers bans the given peers permanently, splitting long lists across
+// several requests. Every peer is validated before anything is sent.
+func (q *QBittorrentClient) BanPeers(peers []netip.AddrPort) error {
+	return q.BanPeersContext(context.Background(), peers)
+}
+
+func (q *QBittorrentClient) BanPeersContext(ctx context.Context, peers []netip.AddrPort) error {
+	formatted := make([]string, len(peers))
+	for i, peer := range peers {
+		if !peer.IsValid() || peer.Port() == 0 || peer.Addr().Zone() != "" {
+			return fmt.Errorf("invalid peer %q", peer)
+		}
+		// Send IPv4-mapped addresses as plain IPv4, the way GetBannedIPs
+		// reports them. AddrPort brackets IPv6 addresses, as the API expects.
+		formatted[i] = netip.AddrPortFrom(peer.Addr().Unmap(), peer.Port()).String()
 	}
 
+	for start := 0; start < len(formatted); start += banPeersBatchSize {
+		end := min(start+banPeersBatchSize, len(formatted))
+		if err := q.banPeers(ctx, formatted[start:end]); err != nil {
+			return fmt.Errorf("banning peers %d to %d of %d: %w", start+1, end, len(formatted), err)
+		}
+	}
 	return nil
 }
 
-func (q *QBittorrentClient) BanPeers(peers string) error {
+func (q *QBittorrentClient) banPeers(ctx context.Context, peers []string) error {
 	data := url.Values{}
-	data.Set("peers", peers)
+	data.Set("peers", strings.Join(peers, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/transfer/banPeers", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/transfer/banPeers", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("ban peers failed with status code: %d", resp.StatusCode)
+	return nil
+}
+
+// GetBannedIPs returns the manually banned IP addresses from the banned_IPs
+// preference, which BanPeers adds to.
+func (q *QBittorrentClient) GetBannedIPs() ([]netip.Addr, error) {
+	return q.GetBannedIPsContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetBannedIPsContext(ctx context.Context) ([]netip.Addr, error) {
+	preferences, err := q.GetApplicationPreferencesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if preferences.BannedIPs == nil {
+		return nil, nil
 	}
 
-	return nil
+	var ips []netip.Addr
+	for _, line := range strings.Split(*preferences.BannedIPs, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		ip, err := netip.ParseAddr(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid banned IP: %w", err)
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
 }
 
 // Torrent Management
-func (q *QBittorrentClient) GetTorrentList() ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/torrents/info", nil)
+func (q *QBittorrentClient) GetTorrentList(options *TorrentListOptions) ([]Torrent, error) {
+	return q.GetTorrentListContext(context.Background(), options)
+}
+
+func (q *QBittorrentClient) GetTorrentListContext(ctx context.Context, options *TorrentListOptions) ([]Torrent, error) {
+	data := options.values()
+	if options != nil && options.Filter.renamed() {
+		stopStart, err := q.hasStopStart(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data.Set("filter", string(options.Filter.forAPI(stopStart)))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/info?"+data.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var torrents []map[string]interface{}
+	var torrents []Torrent
 	err = json.NewDecoder(resp.Body).Decode(&torrents)
 	if err != nil {
 		return nil, err
@@ -505,43 +932,56 @@ func (q *QBittorrentClient) GetTorrentList() ([]map[string]interface{}, error) {
 	return torrents, nil
 }
 
-func (q *QBittorrentClient) GetTorrentGenericProperties(hash string) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/properties?hash=%s", q.baseURL, hash), nil)
+func (q *QBittorrentClient) GetTorrentGenericProperties(hash string) (*TorrentProperties, error) {
+	return q.GetTorrentGenericPropertiesContext(context.Background(), hash)
+}
+
+func (q *QBittorrentClient) GetTorrentGenericPropertiesContext(ctx context.Context, hash string) (*TorrentProperties, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/torrents/properties?hash=%s", q.baseURL, hash), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var properties map[string]interface{}
+	var properties TorrentProperties
 	err = json.NewDecoder(resp.Body).Decode(&properties)
 	if err != nil {
 		return nil, err
 	}
+	// Older servers do not echo the hash.
+	if properties.Hash == "" {
+		properties.Hash = hash
+		if properties.InfoHash == (InfoHash{}) && len(hash) == 40 {
+			properties.InfoHash.V1 = hash
+		}
+	}
 
-	return properties, nil
+	return &properties, nil
 }
 
 // Torrent Management (continued)
-func (q *QBittorrentClient) GetTorrentTrackers(hash string) ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/trackers?hash=%s", q.baseURL, hash), nil)
+func (q *QBittorrentClient) GetTorrentTrackers(hash string) ([]Tracker, error) {
+	return q.GetTorrentTrackersContext(context.Background(), hash)
+}
+
+func (q *QBittorrentClient) GetTorrentTrackersContext(ctx context.Context, hash string) ([]Tracker, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/torrents/trackers?hash=%s", q.baseURL, hash), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var trackers []map[string]interface{}
+	var trackers []Tracker
 	err = json.NewDecoder(resp.Body).Decode(&trackers)
 	if err != nil {
 		return nil, err
@@ -550,20 +990,23 @@ func (q *QBittorrentClient) GetTorrentTrackers(hash string) ([]map[string]interf
 	return trackers, nil
 }
 
-func (q *QBittorrentClient) GetTorrentWebSeeds(hash string) ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/webseeds?hash=%s", q.baseURL, hash), nil)
+func (q *QBittorrentClient) GetTorrentWebSeeds(hash string) ([]WebSeed, error) {
+	return q.GetTorrentWebSeedsContext(context.Background(), hash)
+}
+
+func (q *QBittorrentClient) GetTorrentWebSeedsContext(ctx context.Context, hash string) ([]WebSeed, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/torrents/webseeds?hash=%s", q.baseURL, hash), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var webSeeds []map[string]interface{}
+	var webSeeds []WebSeed
 	err = json.NewDecoder(resp.Body).Decode(&webSeeds)
 	if err != nil {
 		return nil, err
@@ -572,42 +1015,55 @@ func (q *QBittorrentClient) GetTorrentWebSeeds(hash string) ([]map[string]interf
 	return webSeeds, nil
 }
 
-func (q *QBittorrentClient) GetTorrentContents(hash string) ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/files?hash=%s", q.baseURL, hash), nil)
+// GetTorrentContents returns the files of a torrent. If indexes is not
+// empty, only the files with those indexes are returned.
+func (q *QBittorrentClient) GetTorrentContents(hash string, indexes []int) ([]TorrentFile, error) {
+	return q.GetTorrentContentsContext(context.Background(), hash, indexes)
+}
+
+func (q *QBittorrentClient) GetTorrentContentsContext(ctx context.Context, hash string, indexes []int) ([]TorrentFile, error) {
+	params := url.Values{}
+	params.Set("hash", hash)
+	if len(indexes) > 0 {
+		params.Set("indexes", joinInts(indexes))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/files?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var contents []map[string]interface{}
-	err = json.NewDecoder(resp.Body).Decode(&contents)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	return contents, nil
+	return decodeTorrentFiles(body)
 }
 
-func (q *QBittorrentClient) GetTorrentPiecesStates(hash string) ([]string, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/pieceStates?hash=%s", q.baseURL, hash), nil)
+func (q *QBittorrentClient) GetTorrentPiecesStates(hash string) (PieceStates, error) {
+	return q.GetTorrentPiecesStatesContext(context.Background(), hash)
+}
+
+func (q *QBittorrentClient) GetTorrentPiecesStatesContext(ctx context.Context, hash string) (PieceStates, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/torrents/pieceStates?hash=%s", q.baseURL, hash), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	var states []string
+	var states PieceStates
 	err = json.NewDecoder(resp.Body).Decode(&states)
 	if err != nil {
 		return nil, err
@@ -617,13 +1073,16 @@ func (q *QBittorrentClient) GetTorrentPiecesStates(hash string) ([]string, error
 }
 
 func (q *QBittorrentClient) GetTorrentPiecesHashes(hash string) ([]string, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/torrents/pieceHashes?hash=%s", q.baseURL, hash), nil)
+	return q.GetTorrentPiecesHashesContext(context.Background(), hash)
+}
+
+func (q *QBittorrentClient) GetTorrentPiecesHashesContext(ctx context.Context, hash string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/torrents/pieceHashes?hash=%s", q.baseURL, hash), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -639,388 +1098,440 @@ func (q *QBittorrentClient) GetTorrentPiecesHashes(hash string) ([]string, error
 }
 
 func (q *QBittorrentClient) PauseTorrents(hashes []string) error {
+	return q.PauseTorrentsContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) PauseTorrentsContext(ctx context.Context, hashes []string) error {
+	endpoint := "/api/v2/torrents/pause"
+	if stopStart, err := q.hasStopStart(ctx); err != nil {
+		return err
+	} else if stopStart {
+		endpoint = "/api/v2/torrents/stop"
+	}
+
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/pause", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+endpoint, strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("pause torrents failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) ResumeTorrents(hashes []string) error {
+	return q.ResumeTorrentsContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) ResumeTorrentsContext(ctx context.Context, hashes []string) error {
+	endpoint := "/api/v2/torrents/resume"
+	if stopStart, err := q.hasStopStart(ctx); err != nil {
+		return err
+	} else if stopStart {
+		endpoint = "/api/v2/torrents/start"
+	}
+
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/resume", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+endpoint, strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("resume torrents failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) DeleteTorrents(hashes []string, deleteFiles bool) error {
+	return q.DeleteTorrentsContext(context.Background(), hashes, deleteFiles)
+}
+
+func (q *QBittorrentClient) DeleteTorrentsContext(ctx context.Context, hashes []string, deleteFiles bool) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("deleteFiles", fmt.Sprintf("%t", deleteFiles))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/delete", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/delete", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("delete torrents failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RecheckTorrents(hashes []string) error {
+	return q.RecheckTorrentsContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) RecheckTorrentsContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/recheck", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/recheck", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("recheck torrents failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) ReannounceTorrents(hashes []string) error {
+	return q.ReannounceTorrentsContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) ReannounceTorrentsContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/reannounce", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/reannounce", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("reannounce torrents failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) EditTrackers(hash string, originalUrl string, newUrl string) error {
+	return q.EditTrackersContext(context.Background(), hash, originalUrl, newUrl)
+}
+
+func (q *QBittorrentClient) EditTrackersContext(ctx context.Context, hash string, originalUrl string, newUrl string) error {
 	data := url.Values{}
 	data.Set("hash", hash)
-	data.Set("originalUrl", originalUrl)
+	data.Set("origUrl", originalUrl)
 	data.Set("newUrl", newUrl)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/editTracker", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/editTracker", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("edit trackers failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RemoveTrackers(hash string, urls []string) error {
+	return q.RemoveTrackersContext(context.Background(), hash, urls)
+}
+
+func (q *QBittorrentClient) RemoveTrackersContext(ctx context.Context, hash string, urls []string) error {
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("urls", strings.Join(urls, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/removeTrackers", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/removeTrackers", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("remove trackers failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) AddPeers(hash string, peers []string) error {
+	return q.AddPeersContext(context.Background(), hash, peers)
+}
+
+func (q *QBittorrentClient) AddPeersContext(ctx context.Context, hash string, peers []string) error {
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("peers", strings.Join(peers, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/addPeers", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/addPeers", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add peers failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 // Torrent Management (continued)
-func (q *QBittorrentClient) AddNewTorrent(urls []string, options map[string]string) error {
-	data := url.Values{}
-	for key, value := range options {
-		data.Set(key, value)
+func (q *QBittorrentClient) AddNewTorrent(urls []string, options *AddTorrentOptions) error {
+	return q.AddNewTorrentContext(context.Background(), urls, options)
+}
+
+func (q *QBittorrentClient) AddNewTorrentContext(ctx context.Context, urls []string, options *AddTorrentOptions) error {
+	return q.AddTorrentsContext(ctx, urls, nil, options)
+}
+
+func (q *QBittorrentClient) AddTorrents(urls []string, torrents []TorrentUpload, options *AddTorrentOptions) error {
+	return q.AddTorrentsContext(context.Background(), urls, torrents, options)
+}
+
+// AddTorrentsContext adds torrents from URLs or magnet links and from
+// .torrent files in a single multipart/form-data request.
+func (q *QBittorrentClient) AddTorrentsContext(ctx context.Context, urls []string, torrents []TorrentUpload, options *AddTorrentOptions) error {
+	if len(urls) == 0 && len(torrents) == 0 {
+		return fmt.Errorf("no urls or torrent files to add")
+	}
+
+	var api Version
+	if options.needsVersion() {
+		version, err := q.ServerVersionContext(ctx)
+		if err != nil {
+			return err
+		}
+		api = version.API
+	}
+	fields, err := options.values(api)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for key, values := range fields {
+		if err := writer.WriteField(key, values[0]); err != nil {
+			return err
+		}
+	}
+	if len(urls) > 0 {
+		if err := writer.WriteField("urls", strings.Join(urls, "\n")); err != nil {
+			return err
+		}
+	}
+	for _, torrent := range torrents {
+		if err := torrent.writeTo(writer); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
 	}
-	data.Set("urls", strings.Join(urls, "\n"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/add", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/add", &body)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add new torrent failed with status code: %d", resp.StatusCode)
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(string(result)) == "Fails." {
+		return ErrAddTorrentFailed
 	}
 
 	return nil
 }
 
 func (q *QBittorrentClient) AddTrackersToTorrent(hash string, urls []string) error {
+	return q.AddTrackersToTorrentContext(context.Background(), hash, urls)
+}
+
+func (q *QBittorrentClient) AddTrackersToTorrentContext(ctx context.Context, hash string, urls []string) error {
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("urls", strings.Join(urls, "\n"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/addTrackers", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/addTrackers", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add trackers to torrent failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) IncreaseTorrentPriority(hashes []string) error {
+	return q.IncreaseTorrentPriorityContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) IncreaseTorrentPriorityContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/increasePrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/increasePrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("increase torrent priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) DecreaseTorrentPriority(hashes []string) error {
+	return q.DecreaseTorrentPriorityContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) DecreaseTorrentPriorityContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/decreasePrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/decreasePrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("decrease torrent priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) MaximalTorrentPriority(hashes []string) error {
+	return q.MaximalTorrentPriorityContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) MaximalTorrentPriorityContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/topPrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/topPrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("maximal torrent priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) MinimalTorrentPriority(hashes []string) error {
+	return q.MinimalTorrentPriorityContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) MinimalTorrentPriorityContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/bottomPrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/bottomPrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("minimal torrent priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
-func (q *QBittorrentClient) SetFilePriority(hash string, fileIds []int, priority int) error {
+func (q *QBittorrentClient) SetFilePriority(hash string, fileIds []int, priority FilePriority) error {
+	return q.SetFilePriorityContext(context.Background(), hash, fileIds, priority)
+}
+
+func (q *QBittorrentClient) SetFilePriorityContext(ctx context.Context, hash string, fileIds []int, priority FilePriority) error {
 	data := url.Values{}
 	data.Set("hash", hash)
-	data.Set("ids", strings.Trim(strings.Join(strings.Fields(fmt.Sprint(fileIds)), "|"), "[]"))
+	data.Set("id", joinInts(fileIds))
 	data.Set("priority", fmt.Sprintf("%d", priority))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/filePrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/filePrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set file priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetTorrentDownloadLimit(hashes []string) (map[string]int, error) {
+	return q.GetTorrentDownloadLimitContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) GetTorrentDownloadLimitContext(ctx context.Context, hashes []string) (map[string]int, error) {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/torrents/downloadLimit?"+data.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/downloadLimit?"+data.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1036,67 +1547,68 @@ func (q *QBittorrentClient) GetTorrentDownloadLimit(hashes []string) (map[string
 }
 
 func (q *QBittorrentClient) SetTorrentDownloadLimit(hashes []string, limit int) error {
+	return q.SetTorrentDownloadLimitContext(context.Background(), hashes, limit)
+}
+
+func (q *QBittorrentClient) SetTorrentDownloadLimitContext(ctx context.Context, hashes []string, limit int) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("limit", fmt.Sprintf("%d", limit))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setDownloadLimit", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setDownloadLimit", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent download limit failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetTorrentShareLimit(hashes []string, ratioLimit float64, seedingTimeLimit int) error {
+	return q.SetTorrentShareLimitContext(context.Background(), hashes, ratioLimit, seedingTimeLimit)
+}
+
+func (q *QBittorrentClient) SetTorrentShareLimitContext(ctx context.Context, hashes []string, ratioLimit float64, seedingTimeLimit int) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("ratioLimit", fmt.Sprintf("%f", ratioLimit))
 	data.Set("seedingTimeLimit", fmt.Sprintf("%d", seedingTimeLimit))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setShareLimits", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setShareLimits", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent share limit failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetTorrentUploadLimit(hashes []string) (map[string]int, error) {
+	return q.GetTorrentUploadLimitContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) GetTorrentUploadLimitContext(ctx context.Context, hashes []string) (map[string]int, error) {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/torrents/uploadLimit?"+data.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/uploadLimit?"+data.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1112,113 +1624,112 @@ func (q *QBittorrentClient) GetTorrentUploadLimit(hashes []string) (map[string]i
 }
 
 func (q *QBittorrentClient) SetTorrentUploadLimit(hashes []string, limit int) error {
+	return q.SetTorrentUploadLimitContext(context.Background(), hashes, limit)
+}
+
+func (q *QBittorrentClient) SetTorrentUploadLimitContext(ctx context.Context, hashes []string, limit int) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("limit", fmt.Sprintf("%d", limit))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setUploadLimit", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setUploadLimit", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent upload limit failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetTorrentLocation(hashes []string, location string) error {
+	return q.SetTorrentLocationContext(context.Background(), hashes, location)
+}
+
+func (q *QBittorrentClient) SetTorrentLocationContext(ctx context.Context, hashes []string, location string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("location", location)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setLocation", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setLocation", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent location failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetTorrentName(hash string, name string) error {
+	return q.SetTorrentNameContext(context.Background(), hash, name)
+}
+
+func (q *QBittorrentClient) SetTorrentNameContext(ctx context.Context, hash string, name string) error {
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("name", name)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/rename", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/rename", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent name failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetTorrentCategory(hashes []string, category string) error {
+	return q.SetTorrentCategoryContext(context.Background(), hashes, category)
+}
+
+func (q *QBittorrentClient) SetTorrentCategoryContext(ctx context.Context, hashes []string, category string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("category", category)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setCategory", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setCategory", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set torrent category failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetAllCategories() (map[string]map[string]int, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/torrents/categories", nil)
+	return q.GetAllCategoriesContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAllCategoriesContext(ctx context.Context) (map[string]map[string]int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/categories", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1234,137 +1745,135 @@ func (q *QBittorrentClient) GetAllCategories() (map[string]map[string]int, error
 }
 
 func (q *QBittorrentClient) AddNewCategory(category string, savePath string) error {
+	return q.AddNewCategoryContext(context.Background(), category, savePath)
+}
+
+func (q *QBittorrentClient) AddNewCategoryContext(ctx context.Context, category string, savePath string) error {
 	data := url.Values{}
 	data.Set("category", category)
 	data.Set("savePath", savePath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/createCategory", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/createCategory", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add new category failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) EditCategory(category string, savePath string) error {
+	return q.EditCategoryContext(context.Background(), category, savePath)
+}
+
+func (q *QBittorrentClient) EditCategoryContext(ctx context.Context, category string, savePath string) error {
 	data := url.Values{}
 	data.Set("category", category)
 	data.Set("savePath", savePath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/editCategory", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/editCategory", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("edit category failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RemoveCategories(categories []string) error {
+	return q.RemoveCategoriesContext(context.Background(), categories)
+}
+
+func (q *QBittorrentClient) RemoveCategoriesContext(ctx context.Context, categories []string) error {
 	data := url.Values{}
 	data.Set("categories", strings.Join(categories, "\n"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/removeCategories", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/removeCategories", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("remove categories failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) AddTorrentTags(hashes []string, tags []string) error {
+	return q.AddTorrentTagsContext(context.Background(), hashes, tags)
+}
+
+func (q *QBittorrentClient) AddTorrentTagsContext(ctx context.Context, hashes []string, tags []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/addTags", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/addTags", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add torrent tags failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RemoveTorrentTags(hashes []string, tags []string) error {
+	return q.RemoveTorrentTagsContext(context.Background(), hashes, tags)
+}
+
+func (q *QBittorrentClient) RemoveTorrentTagsContext(ctx context.Context, hashes []string, tags []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/removeTags", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/removeTags", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("remove torrent tags failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetAllTags() ([]string, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/torrents/tags", nil)
+	return q.GetAllTagsContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAllTagsContext(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/torrents/tags", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1380,335 +1889,333 @@ func (q *QBittorrentClient) GetAllTags() ([]string, error) {
 }
 
 func (q *QBittorrentClient) CreateTags(tags []string) error {
+	return q.CreateTagsContext(context.Background(), tags)
+}
+
+func (q *QBittorrentClient) CreateTagsContext(ctx context.Context, tags []string) error {
 	data := url.Values{}
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/createTags", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/createTags", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("create tags failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) DeleteTags(tags []string) error {
+	return q.DeleteTagsContext(context.Background(), tags)
+}
+
+func (q *QBittorrentClient) DeleteTagsContext(ctx context.Context, tags []string) error {
 	data := url.Values{}
 	data.Set("tags", strings.Join(tags, ","))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/deleteTags", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/deleteTags", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("delete tags failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetAutomaticTorrentManagement(hashes []string, enable bool) error {
+	return q.SetAutomaticTorrentManagementContext(context.Background(), hashes, enable)
+}
+
+func (q *QBittorrentClient) SetAutomaticTorrentManagementContext(ctx context.Context, hashes []string, enable bool) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("enable", fmt.Sprintf("%t", enable))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setAutoManagement", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setAutoManagement", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set automatic torrent management failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) ToggleSequentialDownload(hashes []string) error {
+	return q.ToggleSequentialDownloadContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) ToggleSequentialDownloadContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/toggleSequentialDownload", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/toggleSequentialDownload", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("toggle sequential download failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetFirstLastPiecePriority(hashes []string) error {
+	return q.SetFirstLastPiecePriorityContext(context.Background(), hashes)
+}
+
+func (q *QBittorrentClient) SetFirstLastPiecePriorityContext(ctx context.Context, hashes []string) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setFirstLastPiecePrio", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setFirstLastPiecePrio", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set first/last piece priority failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetForceStart(hashes []string, enable bool) error {
+	return q.SetForceStartContext(context.Background(), hashes, enable)
+}
+
+func (q *QBittorrentClient) SetForceStartContext(ctx context.Context, hashes []string, enable bool) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("value", fmt.Sprintf("%t", enable))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setForceStart", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setForceStart", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set force start failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetSuperSeeding(hashes []string, enable bool) error {
+	return q.SetSuperSeedingContext(context.Background(), hashes, enable)
+}
+
+func (q *QBittorrentClient) SetSuperSeedingContext(ctx context.Context, hashes []string, enable bool) error {
 	data := url.Values{}
 	data.Set("hashes", strings.Join(hashes, "|"))
 	data.Set("value", fmt.Sprintf("%t", enable))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/setSuperSeeding", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/setSuperSeeding", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set super seeding failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RenameFile(hash string, oldPath string, newPath string) error {
+	return q.RenameFileContext(context.Background(), hash, oldPath, newPath)
+}
+
+func (q *QBittorrentClient) RenameFileContext(ctx context.Context, hash string, oldPath string, newPath string) error {
+	if err := q.requireAPI(ctx, apiRenamePaths, "renameFile"); err != nil {
+		return err
+	}
+
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("oldPath", oldPath)
 	data.Set("newPath", newPath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/renameFile", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/renameFile", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("rename file failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RenameFolder(hash string, oldPath string, newPath string) error {
+	return q.RenameFolderContext(context.Background(), hash, oldPath, newPath)
+}
+
+func (q *QBittorrentClient) RenameFolderContext(ctx context.Context, hash string, oldPath string, newPath string) error {
+	if err := q.requireAPI(ctx, apiRenamePaths, "renameFolder"); err != nil {
+		return err
+	}
+
 	data := url.Values{}
 	data.Set("hash", hash)
 	data.Set("oldPath", oldPath)
 	data.Set("newPath", newPath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/torrents/renameFolder", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/renameFolder", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("rename folder failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 // RSS (Experimental)
 func (q *QBittorrentClient) AddFolder(path string) error {
+	return q.AddFolderContext(context.Background(), path)
+}
+
+func (q *QBittorrentClient) AddFolderContext(ctx context.Context, path string) error {
 	data := url.Values{}
 	data.Set("path", path)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/addFolder", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/addFolder", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add folder failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) AddFeed(urlStr string, path string) error {
+	return q.AddFeedContext(context.Background(), urlStr, path)
+}
+
+func (q *QBittorrentClient) AddFeedContext(ctx context.Context, urlStr string, path string) error {
 	data := url.Values{}
 	data.Set("url", urlStr)
 	data.Set("path", path)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/addFeed", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/addFeed", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("add feed failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RemoveItem(path string) error {
+	return q.RemoveItemContext(context.Background(), path)
+}
+
+func (q *QBittorrentClient) RemoveItemContext(ctx context.Context, path string) error {
 	data := url.Values{}
 	data.Set("path", path)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/removeItem", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/removeItem", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("remove item failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) MoveItem(itemPath string, destPath string) error {
+	return q.MoveItemContext(context.Background(), itemPath, destPath)
+}
+
+func (q *QBittorrentClient) MoveItemContext(ctx context.Context, itemPath string, destPath string) error {
 	data := url.Values{}
 	data.Set("itemPath", itemPath)
 	data.Set("destPath", destPath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/moveItem", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/moveItem", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("move item failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetAllItems() (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/rss/items", nil)
+	return q.GetAllItemsContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAllItemsContext(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/rss/items", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1724,136 +2231,134 @@ func (q *QBittorrentClient) GetAllItems() (map[string]interface{}, error) {
 }
 
 func (q *QBittorrentClient) MarkAsRead(itemPath string, articleId string) error {
+	return q.MarkAsReadContext(context.Background(), itemPath, articleId)
+}
+
+func (q *QBittorrentClient) MarkAsReadContext(ctx context.Context, itemPath string, articleId string) error {
 	data := url.Values{}
 	data.Set("itemPath", itemPath)
 	data.Set("articleId", articleId)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/markAsRead", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/markAsRead", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("mark as read failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RefreshItem(itemPath string) error {
+	return q.RefreshItemContext(context.Background(), itemPath)
+}
+
+func (q *QBittorrentClient) RefreshItemContext(ctx context.Context, itemPath string) error {
 	data := url.Values{}
 	data.Set("itemPath", itemPath)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/refreshItem", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/refreshItem", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("refresh item failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) SetAutoDownloadingRule(ruleName string, ruleDef string) error {
+	return q.SetAutoDownloadingRuleContext(context.Background(), ruleName, ruleDef)
+}
+
+func (q *QBittorrentClient) SetAutoDownloadingRuleContext(ctx context.Context, ruleName string, ruleDef string) error {
 	data := url.Values{}
 	data.Set("ruleName", ruleName)
 	data.Set("ruleDef", ruleDef)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/setRule", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/setRule", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("set auto-downloading rule failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RenameAutoDownloadingRule(ruleName string, newRuleName string) error {
+	return q.RenameAutoDownloadingRuleContext(context.Background(), ruleName, newRuleName)
+}
+
+func (q *QBittorrentClient) RenameAutoDownloadingRuleContext(ctx context.Context, ruleName string, newRuleName string) error {
 	data := url.Values{}
 	data.Set("ruleName", ruleName)
 	data.Set("newRuleName", newRuleName)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/renameRule", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/renameRule", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("rename auto-downloading rule failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) RemoveAutoDownloadingRule(ruleName string) error {
+	return q.RemoveAutoDownloadingRuleContext(context.Background(), ruleName)
+}
+
+func (q *QBittorrentClient) RemoveAutoDownloadingRuleContext(ctx context.Context, ruleName string) error {
 	data := url.Values{}
 	data.Set("ruleName", ruleName)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/rss/removeRule", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/rss/removeRule", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("remove auto-downloading rule failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetAllAutoDownloadingRules() (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/rss/rules", nil)
+	return q.GetAllAutoDownloadingRulesContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetAllAutoDownloadingRulesContext(ctx context.Context) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/rss/rules", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1869,13 +2374,16 @@ func (q *QBittorrentClient) GetAllAutoDownloadingRules() (map[string]interface{}
 }
 
 func (q *QBittorrentClient) GetAllArticlesMatchingRule(ruleName string) ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/rss/matchingArticles?ruleName=%s", q.baseURL, ruleName), nil)
+	return q.GetAllArticlesMatchingRuleContext(context.Background(), ruleName)
+}
+
+func (q *QBittorrentClient) GetAllArticlesMatchingRuleContext(ctx context.Context, ruleName string) ([]map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/rss/matchingArticles?ruleName=%s", q.baseURL, ruleName), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1892,19 +2400,22 @@ func (q *QBittorrentClient) GetAllArticlesMatchingRule(ruleName string) ([]map[s
 
 // Search
 func (q *QBittorrentClient) StartSearch(pattern string, plugins []string, category string) (int, error) {
+	return q.StartSearchContext(context.Background(), pattern, plugins, category)
+}
+
+func (q *QBittorrentClient) StartSearchContext(ctx context.Context, pattern string, plugins []string, category string) (int, error) {
 	data := url.Values{}
 	data.Set("pattern", pattern)
 	data.Set("plugins", strings.Join(plugins, "|"))
 	data.Set("category", category)
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/start", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/start", strings.NewReader(data.Encode()))
 	if err != nil {
 		return 0, err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return 0, err
 	}
@@ -1920,37 +2431,39 @@ func (q *QBittorrentClient) StartSearch(pattern string, plugins []string, catego
 }
 
 func (q *QBittorrentClient) StopSearch(id int) error {
+	return q.StopSearchContext(context.Background(), id)
+}
+
+func (q *QBittorrentClient) StopSearchContext(ctx context.Context, id int) error {
 	data := url.Values{}
 	data.Set("id", fmt.Sprintf("%d", id))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/stop", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/stop", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("stop search failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetSearchStatus(id int) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/search/status?id=%d", q.baseURL, id), nil)
+	return q.GetSearchStatusContext(context.Background(), id)
+}
+
+func (q *QBittorrentClient) GetSearchStatusContext(ctx context.Context, id int) (map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/search/status?id=%d", q.baseURL, id), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1966,13 +2479,16 @@ func (q *QBittorrentClient) GetSearchStatus(id int) (map[string]interface{}, err
 }
 
 func (q *QBittorrentClient) GetSearchResults(id int, limit int, offset int) ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/v2/search/results?id=%d&limit=%d&offset=%d", q.baseURL, id, limit, offset), nil)
+	return q.GetSearchResultsContext(context.Background(), id, limit, offset)
+}
+
+func (q *QBittorrentClient) GetSearchResultsContext(ctx context.Context, id int, limit int, offset int) ([]map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/api/v2/search/results?id=%d&limit=%d&offset=%d", q.baseURL, id, limit, offset), nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -1988,37 +2504,39 @@ func (q *QBittorrentClient) GetSearchResults(id int, limit int, offset int) ([]m
 }
 
 func (q *QBittorrentClient) DeleteSearch(id int) error {
+	return q.DeleteSearchContext(context.Background(), id)
+}
+
+func (q *QBittorrentClient) DeleteSearchContext(ctx context.Context, id int) error {
 	data := url.Values{}
 	data.Set("id", fmt.Sprintf("%d", id))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/delete", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/delete", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("delete search failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) GetSearchPlugins() ([]map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/search/plugins", nil)
+	return q.GetSearchPluginsContext(context.Background())
+}
+
+func (q *QBittorrentClient) GetSearchPluginsContext(ctx context.Context) ([]map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/search/plugins", nil)
 	if err != nil {
 		return nil, err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -2034,94 +2552,90 @@ func (q *QBittorrentClient) GetSearchPlugins() ([]map[string]interface{}, error)
 }
 
 func (q *QBittorrentClient) InstallSearchPlugin(sources []string) error {
+	return q.InstallSearchPluginContext(context.Background(), sources)
+}
+
+func (q *QBittorrentClient) InstallSearchPluginContext(ctx context.Context, sources []string) error {
 	data := url.Values{}
 	data.Set("sources", strings.Join(sources, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/installPlugin", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/installPlugin", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("install search plugin failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) UninstallSearchPlugin(names []string) error {
+	return q.UninstallSearchPluginContext(context.Background(), names)
+}
+
+func (q *QBittorrentClient) UninstallSearchPluginContext(ctx context.Context, names []string) error {
 	data := url.Values{}
 	data.Set("names", strings.Join(names, "|"))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/uninstallPlugin", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/uninstallPlugin", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("uninstall search plugin failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) EnableSearchPlugin(names []string, enable bool) error {
+	return q.EnableSearchPluginContext(context.Background(), names, enable)
+}
+
+func (q *QBittorrentClient) EnableSearchPluginContext(ctx context.Context, names []string, enable bool) error {
 	data := url.Values{}
 	data.Set("names", strings.Join(names, "|"))
 	data.Set("enable", fmt.Sprintf("%t", enable))
 
-	req, err := http.NewRequest("POST", q.baseURL+"/api/v2/search/enablePlugin", strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/search/enablePlugin", strings.NewReader(data.Encode()))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("enable search plugin failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
 }
 
 func (q *QBittorrentClient) UpdateSearchPlugins() error {
-	req, err := http.NewRequest("GET", q.baseURL+"/api/v2/search/updatePlugins", nil)
+	return q.UpdateSearchPluginsContext(context.Background())
+}
+
+func (q *QBittorrentClient) UpdateSearchPluginsContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", q.baseURL+"/api/v2/search/updatePlugins", nil)
 	if err != nil {
 		return err
 	}
-	req.AddCookie(q.cookie)
 
-	resp, err := q.client.Do(req)
+	resp, err := q.do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("update search plugins failed with status code: %d", resp.StatusCode)
-	}
-
 	return nil
-}
\ No newline at end of file
+}
@@ -1,18 +1,55 @@
 package qbittorrent
 
 import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
 )
 
 const (
-	testServerURL = "http://127.0.0.1:8080"
-	testUsername  = "admin"
-	testPassword  = "adminadmin"
+	testUsername = qbittorrenttest.DefaultUsername
+	testPassword = qbittorrenttest.DefaultPassword
 )
 
+// newTestClient returns a client logged in to server, and closes server when
+// the test ends.
+func newTestClient(t *testing.T, server *qbittorrenttest.Server) *QBittorrentClient {
+	t.Helper()
+	t.Cleanup(server.Close)
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.Login(testUsername, testPassword); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return client
+}
+
+// testServerURL returns QBITTORRENT_TEST_URL when set, so tests that only
+// need a login can run against a real daemon, and otherwise starts an
+// in-process fake.
+func testServerURL(t *testing.T) string {
+	if url := os.Getenv("QBITTORRENT_TEST_URL"); url != "" {
+		return url
+	}
+	server := qbittorrenttest.NewServer()
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
 func TestLoginRequirement(t *testing.T) {
-	client, err := NewDefaultClient(testServerURL)
+	client, err := NewDefaultClient(testServerURL(t))
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
@@ -35,6 +72,14 @@ func TestLoginRequirement(t *testing.T) {
 				_, err := client.GetAPIVersion()
 				return err
 			}, ""},
+			{"GetBuildInfo", func() error {
+				_, err := client.GetBuildInfo()
+				return err
+			}, ""},
+			{"GetNetworkInterfaces", func() error {
+				_, err := client.GetNetworkInterfaces()
+				return err
+			}, ""},
 			{"GetApplicationPreferences", func() error {
 				_, err := client.GetApplicationPreferences()
 				return err
@@ -48,15 +93,15 @@ func TestLoginRequirement(t *testing.T) {
 				return err
 			}, ""},
 			{"GetTorrentList", func() error {
-				_, err := client.GetTorrentList()
+				_, err := client.GetTorrentList(nil)
 				return err
 			}, ""},
 			{"GetLog", func() error {
-				_, err := client.GetLog()
+				_, err := client.GetLog(nil)
 				return err
 			}, ""},
 			{"GetPeerLog", func() error {
-				_, err := client.GetPeerLog()
+				_, err := client.GetPeerLog(-1)
 				return err
 			}, ""},
 			{"GetMainData", func() error {
@@ -133,7 +178,7 @@ func TestLoginRequirement(t *testing.T) {
 				return err
 			}, "requires existing torrent"},
 			{"GetTorrentContents", func() error {
-				_, err := client.GetTorrentContents("test")
+				_, err := client.GetTorrentContents("test", nil)
 				return err
 			}, "requires existing torrent"},
 			{"GetTorrentPiecesStates", func() error {
@@ -372,3 +417,144 @@ func TestLoginRequirement(t *testing.T) {
 		}
 	})
 }
+
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := client.GetTorrentListContext(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestReauthenticateOnForbidden(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		sid    int
+		logins int
+		limits []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/api/v2/auth/login" {
+			if r.FormValue("username") != testUsername || r.FormValue("password") != testPassword {
+				w.Write([]byte("Fails."))
+				return
+			}
+			logins++
+			sid++
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: strconv.Itoa(sid)})
+			w.Write([]byte("Ok."))
+			return
+		}
+
+		cookie, err := r.Cookie("SID")
+		if err != nil || cookie.Value != strconv.Itoa(sid) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		limits = append(limits, r.FormValue("limit"))
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	if err := client.Login(testUsername, "wrong"); err == nil {
+		t.Fatal("expected login with a wrong password to fail")
+	}
+	if err := client.Login(testUsername, testPassword); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	// Simulate a daemon restart invalidating the session.
+	mu.Lock()
+	sid++
+	mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.SetGlobalDownloadLimit(1024); err != nil {
+				t.Errorf("SetGlobalDownloadLimit failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if logins != 2 {
+		t.Errorf("expected concurrent callers to share one re-login, got %d logins", logins)
+	}
+	if len(limits) != 10 {
+		t.Fatalf("expected 10 successful requests, got %d", len(limits))
+	}
+	for _, limit := range limits {
+		if limit != "1024" {
+			t.Errorf("replayed request lost its body: limit=%q", limit)
+		}
+	}
+}
+
+func TestApplicationEndpoints(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	client := newTestClient(t, server)
+
+	interfaces, err := client.GetNetworkInterfaces()
+	if err != nil {
+		t.Fatalf("GetNetworkInterfaces failed: %v", err)
+	}
+	if len(interfaces) != 2 || interfaces[1] != (NetworkInterface{Name: "eth0", Value: "eth0"}) {
+		t.Errorf("unexpected interfaces: %+v", interfaces)
+	}
+
+	addresses, err := client.GetNetworkInterfaceAddresses("eth0")
+	if err != nil {
+		t.Fatalf("GetNetworkInterfaceAddresses failed: %v", err)
+	}
+	if len(addresses) != 2 || !addresses[0].Is4() || !addresses[1].Is6() {
+		t.Errorf("unexpected addresses: %v", addresses)
+	}
+	if all, err := client.GetNetworkInterfaceAddresses(""); err != nil || len(all) != 4 {
+		t.Errorf("expected the addresses of every interface, got %v, %v", all, err)
+	}
+
+	if err := client.SendTestEmail(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported from a 4.x server, got %v", err)
+	}
+	// The server version is cached until the next login.
+	server.SetVersion("v5.1.0", "2.11.4")
+	if err := client.Login(testUsername, testPassword); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if err := client.SendTestEmail(); err != nil || server.TestEmailsSent() != 1 {
+		t.Errorf("SendTestEmail failed: %v", err)
+	}
+
+	if err := client.Shutdown(); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if !server.ShutdownRequested() {
+		t.Error("expected the server to be shut down")
+	}
+}
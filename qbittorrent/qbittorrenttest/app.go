@@ -0,0 +1,291 @@
+package qbittorrenttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+type LogEntry struct {
+	ID        int    `json:"id"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"`
+	Type      int    `json:"type"`
+}
+
+type PeerLogEntry struct {
+	ID        int    `json:"id"`
+	IP        string `json:"ip"`
+	Timestamp int64  `json:"timestamp"`
+	Blocked   bool   `json:"blocked"`
+	Reason    string `json:"reason"`
+}
+
+const defaultSavePath = "/downloads"
+
+func defaultPreferences() map[string]any {
+	return map[string]any{
+		"save_path":            defaultSavePath,
+		"temp_path_enabled":    false,
+		"temp_path":            defaultSavePath + "/incomplete",
+		"listen_port":          6881,
+		"dht":                  true,
+		"pex":                  true,
+		"lsd":                  true,
+		"queueing_enabled":     true,
+		"max_active_downloads": 3,
+		"max_active_torrents":  5,
+		"max_active_uploads":   3,
+		"dl_limit":             0,
+		"up_limit":             0,
+		"alt_dl_limit":         10240,
+		"alt_up_limit":         10240,
+		"banned_IPs":           "",
+		"web_ui_port":          8080,
+		"web_ui_username":      DefaultUsername,
+	}
+}
+
+func (s *Server) routeApp(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		cookie, _ := r.Cookie("SID")
+		delete(s.sessions, cookie.Value)
+	})
+
+	s.handle(mux, "/api/v2/app/version", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, "%s", s.appVersion)
+	})
+
+	s.handle(mux, "/api/v2/app/webapiVersion", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, "%s", s.apiVersion)
+	})
+
+	s.handle(mux, "/api/v2/app/buildInfo", s.sinceAPI(2, 3, 0, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"qt":         "6.7.3",
+			"libtorrent": "2.0.10.0",
+			"boost":      "1.86.0",
+			"openssl":    "3.3.2",
+			"zlib":       "1.3.1",
+			"bitness":    64,
+			"platform":   "linux",
+		})
+	}))
+
+	s.handle(mux, "/api/v2/app/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		s.shutdown = true
+		s.sessions = make(map[string]bool)
+	})
+
+	s.handle(mux, "/api/v2/app/networkInterfaceList", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, []map[string]string{
+			{"name": "lo", "value": "lo"},
+			{"name": "eth0", "value": "eth0"},
+		})
+	})
+
+	s.handle(mux, "/api/v2/app/networkInterfaceAddressList", func(w http.ResponseWriter, r *http.Request) {
+		addresses := map[string][]string{
+			"lo":   {"127.0.0.1", "::1"},
+			"eth0": {"192.0.2.10", "2001:db8::10"},
+		}
+		iface := r.FormValue("iface")
+		if iface == "" {
+			writeJSON(w, append(addresses["lo"], addresses["eth0"]...))
+			return
+		}
+		writeJSON(w, nonNil(addresses[iface]))
+	})
+
+	s.handle(mux, "/api/v2/app/sendTestEmail", s.sinceAPI(2, 11, 4, func(w http.ResponseWriter, r *http.Request) {
+		s.testEmails++
+	}))
+
+	s.handle(mux, "/api/v2/app/defaultSavePath", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, "%s", s.preferences["save_path"])
+	})
+
+	s.handle(mux, "/api/v2/app/preferences", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.preferences)
+	})
+
+	s.handle(mux, "/api/v2/app/setPreferences", func(w http.ResponseWriter, r *http.Request) {
+		var changes map[string]any
+		if err := json.Unmarshal([]byte(r.FormValue("json")), &changes); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for key, value := range changes {
+			s.preferences[key] = value
+		}
+	})
+}
+
+func (s *Server) routeLog(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/log/main", func(w http.ResponseWriter, r *http.Request) {
+		lastKnownID, err := formInt(r, "last_known_id", -1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		types := map[int]bool{}
+		for typ, key := range map[int]string{1: "normal", 2: "info", 4: "warning", 8: "critical"} {
+			types[typ] = r.FormValue(key) != "false"
+		}
+
+		entries := []LogEntry{}
+		for _, entry := range s.logs {
+			if entry.ID > lastKnownID && types[entry.Type] {
+				entries = append(entries, entry)
+			}
+		}
+		writeJSON(w, entries)
+	})
+
+	s.handle(mux, "/api/v2/log/peers", func(w http.ResponseWriter, r *http.Request) {
+		lastKnownID, err := formInt(r, "last_known_id", -1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		entries := []PeerLogEntry{}
+		for _, entry := range s.peerLog {
+			if entry.ID > lastKnownID {
+				entries = append(entries, entry)
+			}
+		}
+		writeJSON(w, entries)
+	})
+}
+
+func (s *Server) routeTransfer(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/transfer/info", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.transferInfo())
+	})
+
+	s.handle(mux, "/api/v2/transfer/speedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		if s.altSpeedLimits {
+			writeText(w, "1")
+		} else {
+			writeText(w, "0")
+		}
+	})
+
+	s.handle(mux, "/api/v2/transfer/toggleSpeedLimitsMode", func(w http.ResponseWriter, r *http.Request) {
+		s.altSpeedLimits = !s.altSpeedLimits
+	})
+
+	s.handle(mux, "/api/v2/transfer/setSpeedLimitsMode", s.sinceAPI(2, 11, 3, func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("mode") {
+		case "0":
+			s.altSpeedLimits = false
+		case "1":
+			s.altSpeedLimits = true
+		default:
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+		}
+	}))
+
+	s.handle(mux, "/api/v2/transfer/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, "%d", s.dlLimit)
+	})
+
+	s.handle(mux, "/api/v2/transfer/setDownloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := strconv.ParseInt(r.FormValue("limit"), 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.dlLimit = limit
+	})
+
+	s.handle(mux, "/api/v2/transfer/uploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		writeText(w, "%d", s.upLimit)
+	})
+
+	s.handle(mux, "/api/v2/transfer/setUploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		limit, err := strconv.ParseInt(r.FormValue("limit"), 10, 64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.upLimit = limit
+	})
+
+	s.handle(mux, "/api/v2/transfer/banPeers", func(w http.ResponseWriter, r *http.Request) {
+		banned, _ := s.preferences["banned_IPs"].(string)
+		for _, peer := range strings.Split(r.FormValue("peers"), "|") {
+			addr, err := netip.ParseAddrPort(peer)
+			if err != nil {
+				continue
+			}
+			s.bannedPeers = append(s.bannedPeers, peer)
+			if ip := addr.Addr().String(); !contains(strings.Split(banned, "\n"), ip) {
+				banned = strings.TrimPrefix(banned+"\n"+ip, "\n")
+			}
+		}
+		s.preferences["banned_IPs"] = banned
+	})
+}
+
+func (s *Server) transferInfo() map[string]any {
+	var dlSpeed, upSpeed, dlData, upData int64
+	for _, torrent := range s.torrents {
+		dlSpeed += torrent.DlSpeed
+		upSpeed += torrent.UpSpeed
+		dlData += torrent.Downloaded
+		upData += torrent.Uploaded
+	}
+
+	status := "disconnected"
+	if len(s.sessions) > 0 {
+		status = "connected"
+	}
+
+	return map[string]any{
+		"connection_status":    status,
+		"dht_nodes":            0,
+		"dl_info_data":         dlData,
+		"dl_info_speed":        dlSpeed,
+		"dl_rate_limit":        s.dlLimit,
+		"up_info_data":         upData,
+		"up_info_speed":        upSpeed,
+		"up_rate_limit":        s.upLimit,
+		"use_alt_speed_limits": s.altSpeedLimits,
+		"queueing":             s.preferences["queueing_enabled"],
+		"refresh_interval":     1500,
+	}
+}
+
+// serverState extends transferInfo with the statistics sync/maindata adds.
+func (s *Server) serverState() map[string]any {
+	var uploaded, downloaded int64
+	for _, torrent := range s.torrents {
+		uploaded += torrent.Uploaded
+		downloaded += torrent.Downloaded
+	}
+	ratio := "0.00"
+	if downloaded > 0 {
+		ratio = strconv.FormatFloat(float64(uploaded)/float64(downloaded), 'f', 2, 64)
+	}
+
+	state := s.transferInfo()
+	state["alltime_dl"] = downloaded
+	state["alltime_ul"] = uploaded
+	state["global_ratio"] = ratio
+	state["free_space_on_disk"] = int64(500) << 30
+	state["queued_io_jobs"] = 0
+	state["average_time_queue"] = 0
+	state["total_buffers_size"] = 0
+	state["total_queued_size"] = 0
+	state["total_peer_connections"] = 0
+	state["total_wasted_session"] = 0
+	state["read_cache_hits"] = "0"
+	state["read_cache_overload"] = "0"
+	state["write_cache_overload"] = "0"
+	return state
+}
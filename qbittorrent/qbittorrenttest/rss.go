@@ -0,0 +1,174 @@
+package qbittorrenttest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// rssSeparator separates folder names in RSS item paths.
+const rssSeparator = `\`
+
+type rssItem struct {
+	folder   bool
+	url      string
+	uid      string
+	articles []map[string]any
+}
+
+func (s *Server) routeRSS(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/rss/items", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.rssTree(r.FormValue("withData") == "true"))
+	})
+	s.handle(mux, "/api/v2/rss/addFolder", func(w http.ResponseWriter, r *http.Request) {
+		s.addRSSItem(w, r.FormValue("path"), &rssItem{folder: true})
+	})
+	s.handle(mux, "/api/v2/rss/addFeed", func(w http.ResponseWriter, r *http.Request) {
+		u := r.FormValue("url")
+		if u == "" {
+			http.Error(w, "Invalid feed URL", http.StatusConflict)
+			return
+		}
+		itemPath := r.FormValue("path")
+		if itemPath == "" {
+			itemPath = u
+		}
+		sum := sha1.Sum([]byte(u))
+		s.addRSSItem(w, itemPath, &rssItem{url: u, uid: hex.EncodeToString(sum[:16])})
+	})
+	s.handle(mux, "/api/v2/rss/removeItem", func(w http.ResponseWriter, r *http.Request) {
+		itemPath := r.FormValue("path")
+		if _, ok := s.rssItems[itemPath]; !ok {
+			http.Error(w, "Item doesn't exist", http.StatusConflict)
+			return
+		}
+		for p := range s.rssItems {
+			if p == itemPath || strings.HasPrefix(p, itemPath+rssSeparator) {
+				delete(s.rssItems, p)
+			}
+		}
+	})
+	s.handle(mux, "/api/v2/rss/moveItem", func(w http.ResponseWriter, r *http.Request) {
+		itemPath, destPath := r.FormValue("itemPath"), r.FormValue("destPath")
+		if _, ok := s.rssItems[itemPath]; !ok {
+			http.Error(w, "Item doesn't exist", http.StatusConflict)
+			return
+		}
+		if _, ok := s.rssItems[destPath]; ok {
+			http.Error(w, "Destination already exists", http.StatusConflict)
+			return
+		}
+		for p, item := range s.rssItems {
+			if p == itemPath || strings.HasPrefix(p, itemPath+rssSeparator) {
+				delete(s.rssItems, p)
+				s.rssItems[destPath+strings.TrimPrefix(p, itemPath)] = item
+			}
+		}
+	})
+	s.handle(mux, "/api/v2/rss/markAsRead", func(w http.ResponseWriter, r *http.Request) {
+		itemPath, articleID := r.FormValue("itemPath"), r.FormValue("articleId")
+		for p, item := range s.rssItems {
+			if p != itemPath && !strings.HasPrefix(p, itemPath+rssSeparator) {
+				continue
+			}
+			for _, article := range item.articles {
+				if articleID == "" || article["id"] == articleID {
+					article["isRead"] = true
+				}
+			}
+		}
+	})
+	s.handle(mux, "/api/v2/rss/refreshItem", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.rssItems[r.FormValue("itemPath")]; !ok {
+			http.Error(w, "Item doesn't exist", http.StatusConflict)
+		}
+	})
+
+	s.handle(mux, "/api/v2/rss/setRule", func(w http.ResponseWriter, r *http.Request) {
+		def := json.RawMessage(r.FormValue("ruleDef"))
+		if r.FormValue("ruleName") == "" || !json.Valid(def) {
+			http.Error(w, "Invalid rule", http.StatusBadRequest)
+			return
+		}
+		s.rssRules[r.FormValue("ruleName")] = def
+	})
+	s.handle(mux, "/api/v2/rss/renameRule", func(w http.ResponseWriter, r *http.Request) {
+		def, ok := s.rssRules[r.FormValue("ruleName")]
+		if !ok {
+			http.Error(w, "Rule doesn't exist", http.StatusConflict)
+			return
+		}
+		delete(s.rssRules, r.FormValue("ruleName"))
+		s.rssRules[r.FormValue("newRuleName")] = def
+	})
+	s.handle(mux, "/api/v2/rss/removeRule", func(w http.ResponseWriter, r *http.Request) {
+		delete(s.rssRules, r.FormValue("ruleName"))
+	})
+	s.handle(mux, "/api/v2/rss/rules", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.rssRules)
+	})
+	s.handle(mux, "/api/v2/rss/matchingArticles", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := s.rssRules[r.FormValue("ruleName")]; !ok {
+			http.Error(w, "Rule doesn't exist", http.StatusConflict)
+			return
+		}
+		writeJSON(w, map[string][]string{})
+	})
+}
+
+func (s *Server) addRSSItem(w http.ResponseWriter, itemPath string, item *rssItem) {
+	if itemPath == "" {
+		http.Error(w, "Path is empty", http.StatusConflict)
+		return
+	}
+	if _, ok := s.rssItems[itemPath]; ok {
+		http.Error(w, "Item already exists", http.StatusConflict)
+		return
+	}
+	if i := strings.LastIndex(itemPath, rssSeparator); i >= 0 {
+		if parent, ok := s.rssItems[itemPath[:i]]; !ok || !parent.folder {
+			http.Error(w, "Parent folder doesn't exist", http.StatusConflict)
+			return
+		}
+	}
+	s.rssItems[itemPath] = item
+}
+
+// rssTree nests the flat item paths the way rss/items reports them:
+// folders are objects keyed by child name, feeds are objects with a uid and
+// url.
+func (s *Server) rssTree(withData bool) map[string]any {
+	paths := make([]string, 0, len(s.rssItems))
+	for p := range s.rssItems {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	root := map[string]any{}
+	for _, p := range paths {
+		item := s.rssItems[p]
+		parts := strings.Split(p, rssSeparator)
+		parent := root
+		for _, part := range parts[:len(parts)-1] {
+			parent = parent[part].(map[string]any)
+		}
+		name := parts[len(parts)-1]
+		if item.folder {
+			parent[name] = map[string]any{}
+			continue
+		}
+		feed := map[string]any{"uid": item.uid, "url": item.url}
+		if withData {
+			feed["title"] = ""
+			feed["lastBuildDate"] = ""
+			feed["isLoading"] = false
+			feed["hasError"] = false
+			feed["articles"] = nonNil(item.articles)
+		}
+		parent[name] = feed
+	}
+	return root
+}
@@ -0,0 +1,195 @@
+package qbittorrenttest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// maxSearches is how many searches may run at once before search/start
+// answers 409, as on a real server.
+const maxSearches = 5
+
+type SearchResult struct {
+	FileName   string `json:"fileName"`
+	FileURL    string `json:"fileUrl"`
+	FileSize   int64  `json:"fileSize"`
+	NbSeeders  int    `json:"nbSeeders"`
+	NbLeechers int    `json:"nbLeechers"`
+	SiteURL    string `json:"siteUrl"`
+	DescrLink  string `json:"descrLink"`
+}
+
+type SearchCategory struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type SearchPlugin struct {
+	Enabled             bool             `json:"enabled"`
+	FullName            string           `json:"fullName"`
+	Name                string           `json:"name"`
+	SupportedCategories []SearchCategory `json:"supportedCategories"`
+	URL                 string           `json:"url"`
+	Version             string           `json:"version"`
+}
+
+type search struct {
+	id      int
+	status  string
+	results []SearchResult
+}
+
+// SetSearchResults sets the results a search can find. A search finishes
+// immediately with the results whose FileName contains its pattern.
+func (s *Server) SetSearchResults(results []SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchResults = append([]SearchResult(nil), results...)
+}
+
+func (s *Server) searchByID(w http.ResponseWriter, r *http.Request) (*search, bool) {
+	id, err := formInt(r, "id", -1)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	job, ok := s.searches[id]
+	if !ok {
+		http.Error(w, "Search job not found", http.StatusNotFound)
+		return nil, false
+	}
+	return job, true
+}
+
+func (s *Server) routeSearch(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/search/start", func(w http.ResponseWriter, r *http.Request) {
+		pattern := r.FormValue("pattern")
+		if pattern == "" {
+			http.Error(w, "Pattern is empty", http.StatusBadRequest)
+			return
+		}
+		running := 0
+		for _, job := range s.searches {
+			if job.status == "Running" {
+				running++
+			}
+		}
+		if running >= maxSearches {
+			http.Error(w, "Unable to create more than 5 concurrent searches.", http.StatusConflict)
+			return
+		}
+
+		s.nextSearchID++
+		job := &search{id: s.nextSearchID, status: "Stopped", results: []SearchResult{}}
+		for _, result := range s.searchResults {
+			if strings.Contains(strings.ToLower(result.FileName), strings.ToLower(pattern)) {
+				job.results = append(job.results, result)
+			}
+		}
+		s.searches[job.id] = job
+		writeJSON(w, map[string]int{"id": job.id})
+	})
+	s.handle(mux, "/api/v2/search/stop", func(w http.ResponseWriter, r *http.Request) {
+		if job, ok := s.searchByID(w, r); ok {
+			job.status = "Stopped"
+		}
+	})
+	s.handle(mux, "/api/v2/search/status", func(w http.ResponseWriter, r *http.Request) {
+		statuses := []map[string]any{}
+		status := func(job *search) map[string]any {
+			return map[string]any{"id": job.id, "status": job.status, "total": len(job.results)}
+		}
+		if r.FormValue("id") != "" {
+			job, ok := s.searchByID(w, r)
+			if !ok {
+				return
+			}
+			statuses = append(statuses, status(job))
+		} else {
+			for id := 1; id <= s.nextSearchID; id++ {
+				if job, ok := s.searches[id]; ok {
+					statuses = append(statuses, status(job))
+				}
+			}
+		}
+		writeJSON(w, statuses)
+	})
+	s.handle(mux, "/api/v2/search/results", func(w http.ResponseWriter, r *http.Request) {
+		job, ok := s.searchByID(w, r)
+		if !ok {
+			return
+		}
+		limit, _ := formInt(r, "limit", 0)
+		offset, _ := formInt(r, "offset", 0)
+		if offset < 0 {
+			offset += len(job.results)
+		}
+		if offset < 0 || offset > len(job.results) {
+			http.Error(w, "Offset is out of range", http.StatusConflict)
+			return
+		}
+		results := job.results[offset:]
+		if limit > 0 && limit < len(results) {
+			results = results[:limit]
+		}
+		writeJSON(w, map[string]any{
+			"results": results,
+			"status":  job.status,
+			"total":   len(job.results),
+		})
+	})
+	s.handle(mux, "/api/v2/search/delete", func(w http.ResponseWriter, r *http.Request) {
+		if job, ok := s.searchByID(w, r); ok {
+			delete(s.searches, job.id)
+		}
+	})
+
+	s.handle(mux, "/api/v2/search/plugins", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, nonNil(s.plugins))
+	})
+	s.handle(mux, "/api/v2/search/installPlugin", func(w http.ResponseWriter, r *http.Request) {
+		for _, source := range strings.Split(r.FormValue("sources"), "|") {
+			if source == "" {
+				continue
+			}
+			name := source[strings.LastIndex(source, "/")+1:]
+			name = strings.TrimSuffix(name, ".py")
+			s.plugins = append(s.plugins, SearchPlugin{
+				Enabled:             true,
+				FullName:            name,
+				Name:                name,
+				SupportedCategories: []SearchCategory{{ID: "all", Name: "All categories"}},
+				URL:                 source,
+				Version:             "1.0",
+			})
+		}
+	})
+	s.handle(mux, "/api/v2/search/uninstallPlugin", func(w http.ResponseWriter, r *http.Request) {
+		names := strings.Split(r.FormValue("names"), "|")
+		var kept []SearchPlugin
+		for _, plugin := range s.plugins {
+			if !contains(names, plugin.Name) {
+				kept = append(kept, plugin)
+			}
+		}
+		s.plugins = kept
+	})
+	s.handle(mux, "/api/v2/search/enablePlugin", func(w http.ResponseWriter, r *http.Request) {
+		names := strings.Split(r.FormValue("names"), "|")
+		for i := range s.plugins {
+			if contains(names, s.plugins[i].Name) {
+				s.plugins[i].Enabled = r.FormValue("enable") == "true"
+			}
+		}
+	})
+	s.handle(mux, "/api/v2/search/updatePlugins", func(w http.ResponseWriter, r *http.Request) {})
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+// Package qbittorrenttest provides an in-memory fake of the qBittorrent Web
+// API for testing clients without a running daemon.
+//
+// The fake implements authentication, application settings, logs, transfer
+// info, torrents, categories, tags, sync/maindata with incremental rid
+// updates, RSS and search, keeping all state in memory. It follows the
+// status codes of a real server where a client is likely to depend on them,
+// but it does not download anything.
+package qbittorrenttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultUsername = "admin"
+	DefaultPassword = "adminadmin"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu sync.Mutex
+
+	username string
+	password string
+	sessions map[string]bool
+	nextSID  int
+
+	appVersion  string
+	apiVersion  string
+	preferences map[string]any
+	shutdown    bool
+	testEmails  int
+	requests    map[string]int
+
+	torrents   map[string]*Torrent
+	order      []string
+	categories map[string]Category
+	tags       map[string]bool
+
+	altSpeedLimits bool
+	dlLimit        int64
+	upLimit        int64
+	bannedPeers    []string
+
+	logs    []LogEntry
+	peerLog []PeerLogEntry
+
+	rid       int
+	snapshots map[int]*mainData
+
+	peerRid       int
+	peerSnapshots map[string]map[int]map[string]map[string]any
+
+	rssItems map[string]*rssItem
+	rssRules map[string]json.RawMessage
+
+	searches      map[int]*search
+	nextSearchID  int
+	searchResults []SearchResult
+	plugins       []SearchPlugin
+}
+
+// NewServer starts a fake server accepting DefaultUsername and
+// DefaultPassword. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		username:    DefaultUsername,
+		password:    DefaultPassword,
+		sessions:    make(map[string]bool),
+		requests:    make(map[string]int),
+		appVersion:  "v4.6.7",
+		apiVersion:  "2.9.3",
+		preferences: defaultPreferences(),
+		torrents:    make(map[string]*Torrent),
+		categories:  make(map[string]Category),
+		tags:        make(map[string]bool),
+		snapshots:   make(map[int]*mainData),
+
+		peerSnapshots: make(map[string]map[int]map[string]map[string]any),
+		rssItems:      make(map[string]*rssItem),
+		rssRules:      make(map[string]json.RawMessage),
+		searches:      make(map[int]*search),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v2/auth/login", s.login)
+	s.routeApp(mux)
+	s.routeLog(mux)
+	s.routeSync(mux)
+	s.routeTransfer(mux)
+	s.routeTorrents(mux)
+	s.routeRSS(mux)
+	s.routeSearch(mux)
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// SetCredentials changes the username and password accepted by the server.
+func (s *Server) SetCredentials(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username = username
+	s.password = password
+}
+
+// SetVersion changes the application version (e.g. "v5.0.0") and Web API
+// version (e.g. "2.11.0") reported by the server.
+func (s *Server) SetVersion(app, api string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appVersion = app
+	s.apiVersion = api
+}
+
+// ExpireSessions invalidates every session, as a daemon restart would.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = make(map[string]bool)
+}
+
+// ShutdownRequested reports whether a client called app/shutdown. The fake
+// keeps serving, but every session is invalidated.
+func (s *Server) ShutdownRequested() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shutdown
+}
+
+// TestEmailsSent returns how many times app/sendTestEmail was called.
+func (s *Server) TestEmailsSent() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.testEmails
+}
+
+// Requests returns how many requests were made to path, such as
+// "/api/v2/torrents/trackers", including ones rejected by the server.
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// AddLog appends a message to the main log. typ is one of the log type
+// flags used by the API: 1 normal, 2 info, 4 warning, 8 critical.
+func (s *Server) AddLog(message string, typ int) LogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := LogEntry{
+		ID:        len(s.logs),
+		Message:   message,
+		Timestamp: time.Now().UnixMilli(),
+		Type:      typ,
+	}
+	s.logs = append(s.logs, entry)
+	return entry
+}
+
+// AddPeerLog appends an entry to the peer log.
+func (s *Server) AddPeerLog(ip string, blocked bool, reason string) PeerLogEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := PeerLogEntry{
+		ID:        len(s.peerLog),
+		IP:        ip,
+		Timestamp: time.Now().UnixMilli(),
+		Blocked:   blocked,
+		Reason:    reason,
+	}
+	s.peerLog = append(s.peerLog, entry)
+	return entry
+}
+
+// BannedPeers returns every valid peer passed to transfer/banPeers so far.
+func (s *Server) BannedPeers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.bannedPeers...)
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.FormValue("username") != s.username || r.FormValue("password") != s.password {
+		w.Write([]byte("Fails."))
+		return
+	}
+
+	s.nextSID++
+	sid := strconv.Itoa(s.nextSID)
+	s.sessions[sid] = true
+	http.SetCookie(w, &http.Cookie{Name: "SID", Value: sid, Path: "/", HttpOnly: true})
+	w.Write([]byte("Ok."))
+}
+
+// handle registers an endpoint that requires a valid session. Handlers run
+// with s.mu held.
+func (s *Server) handle(mux *http.ServeMux, path string, fn http.HandlerFunc) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.requests[r.URL.Path]++
+		cookie, err := r.Cookie("SID")
+		if err != nil || !s.sessions[cookie.Value] {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if err := parseForm(r); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fn(w, r)
+	})
+}
+
+func parseForm(r *http.Request) error {
+	if r.Header.Get("Content-Type") != "" && r.Method == http.MethodPost {
+		if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return err
+		}
+	}
+	return r.ParseForm()
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeText(w http.ResponseWriter, format string, args ...any) {
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	fmt.Fprintf(w, format, args...)
+}
+
+func formInt(r *http.Request, key string, def int) (int, error) {
+	value := r.FormValue(key)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
@@ -0,0 +1,63 @@
+package qbittorrenttest_test
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+// TestVersionGates checks the fake's own gating with plain HTTP requests, so
+// it does not depend on the client agreeing with it.
+func TestVersionGates(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	for _, tt := range []struct {
+		path      string
+		form      url.Values
+		below, at string
+	}{
+		{"/api/v2/app/sendTestEmail", nil, "2.11.3", "2.11.4"},
+		{"/api/v2/transfer/setSpeedLimitsMode", url.Values{"mode": {"1"}}, "2.11.2", "2.11.3"},
+		{"/api/v2/torrents/addWebSeeds", url.Values{"hash": {hash}, "urls": {"http://b.example/ubuntu.iso"}}, "2.11.2", "2.11.3"},
+		{"/api/v2/torrents/editWebSeed", url.Values{"hash": {hash}, "origUrl": {"http://a.example/ubuntu.iso"}, "newUrl": {"https://a.example/ubuntu.iso"}}, "2.11.2", "2.11.3"},
+		{"/api/v2/torrents/removeWebSeeds", url.Values{"hash": {hash}, "urls": {"http://a.example/ubuntu.iso"}}, "2.11.2", "2.11.3"},
+	} {
+		t.Run(tt.path, func(t *testing.T) {
+			server := qbittorrenttest.NewServer()
+			defer server.Close()
+			server.AddTorrent(qbittorrenttest.Torrent{Hash: hash, WebSeeds: []string{"http://a.example/ubuntu.iso"}})
+
+			jar, _ := cookiejar.New(nil)
+			client := &http.Client{Jar: jar}
+			resp, err := client.PostForm(server.URL+"/api/v2/auth/login", url.Values{
+				"username": {qbittorrenttest.DefaultUsername},
+				"password": {qbittorrenttest.DefaultPassword},
+			})
+			if err != nil {
+				t.Fatalf("login failed: %v", err)
+			}
+			resp.Body.Close()
+
+			for _, step := range []struct {
+				api  string
+				want int
+			}{
+				{tt.below, http.StatusNotFound},
+				{tt.at, http.StatusOK},
+			} {
+				server.SetVersion("v5.1.0", step.api)
+				resp, err := client.PostForm(server.URL+tt.path, tt.form)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != step.want {
+					t.Errorf("Web API %s: status %d, want %d", step.api, resp.StatusCode, step.want)
+				}
+			}
+		})
+	}
+}
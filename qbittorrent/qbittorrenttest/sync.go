@@ -0,0 +1,211 @@
+package qbittorrenttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// maxSnapshots bounds how many past responses are kept for computing
+// incremental updates; older rids get a full update.
+const maxSnapshots = 16
+
+type mainData struct {
+	torrents    map[string]map[string]any
+	categories  map[string]Category
+	tags        []string
+	serverState map[string]any
+}
+
+func (s *Server) currentMainData() *mainData {
+	data := &mainData{
+		torrents:    make(map[string]map[string]any, len(s.torrents)),
+		categories:  make(map[string]Category, len(s.categories)),
+		serverState: make(map[string]any),
+	}
+	for hash, torrent := range s.torrents {
+		var fields map[string]any
+		raw, _ := json.Marshal(torrent)
+		json.Unmarshal(raw, &fields)
+		delete(fields, "hash")
+		data.torrents[hash] = fields
+	}
+	for name, category := range s.categories {
+		data.categories[name] = category
+	}
+	for tag := range s.tags {
+		data.tags = append(data.tags, tag)
+	}
+	sort.Strings(data.tags)
+	// Round trip so values compare equal to the torrents' decoded fields.
+	raw, _ := json.Marshal(s.serverState())
+	json.Unmarshal(raw, &data.serverState)
+	return data
+}
+
+func (s *Server) routeSync(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/sync/maindata", func(w http.ResponseWriter, r *http.Request) {
+		rid, err := formInt(r, "rid", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		current := s.currentMainData()
+		response := map[string]any{}
+		if previous, ok := s.snapshots[rid]; ok && rid > 0 {
+			diffMainData(response, previous, current)
+		} else {
+			response["full_update"] = true
+			response["torrents"] = current.torrents
+			response["categories"] = current.categories
+			response["tags"] = nonNil(current.tags)
+			response["server_state"] = current.serverState
+		}
+
+		s.rid++
+		s.snapshots[s.rid] = current
+		delete(s.snapshots, s.rid-maxSnapshots)
+		response["rid"] = s.rid
+		writeJSON(w, response)
+	})
+
+	s.handle(mux, "/api/v2/sync/torrentPeers", func(w http.ResponseWriter, r *http.Request) {
+		torrent, ok := s.torrents[r.FormValue("hash")]
+		if !ok {
+			http.Error(w, "Torrent hash was not found", http.StatusNotFound)
+			return
+		}
+		rid, err := formInt(r, "rid", 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		current := make(map[string]map[string]any, len(torrent.Peers))
+		for key, peer := range torrent.Peers {
+			var fields map[string]any
+			raw, _ := json.Marshal(peer)
+			json.Unmarshal(raw, &fields)
+			current[key] = fields
+		}
+
+		response := map[string]any{"show_flags": true}
+		if previous, ok := s.peerSnapshots[torrent.Hash][rid]; ok && rid > 0 {
+			peers := map[string]map[string]any{}
+			for key, fields := range current {
+				if changed := diffFields(previous[key], fields); len(changed) > 0 {
+					peers[key] = changed
+				}
+			}
+			if len(peers) > 0 {
+				response["peers"] = peers
+			}
+			var removed []string
+			for key := range previous {
+				if _, ok := current[key]; !ok {
+					removed = append(removed, key)
+				}
+			}
+			if len(removed) > 0 {
+				sort.Strings(removed)
+				response["peers_removed"] = removed
+			}
+		} else {
+			response["full_update"] = true
+			response["peers"] = current
+		}
+
+		s.peerRid++
+		snapshots := s.peerSnapshots[torrent.Hash]
+		if snapshots == nil {
+			snapshots = make(map[int]map[string]map[string]any)
+			s.peerSnapshots[torrent.Hash] = snapshots
+		}
+		snapshots[s.peerRid] = current
+		delete(snapshots, s.peerRid-maxSnapshots)
+		response["rid"] = s.peerRid
+		writeJSON(w, response)
+	})
+}
+
+// diffMainData fills response with what changed from previous to current,
+// sending only changed fields of torrents and server_state.
+func diffMainData(response map[string]any, previous, current *mainData) {
+	torrents := map[string]map[string]any{}
+	for hash, fields := range current.torrents {
+		if changed := diffFields(previous.torrents[hash], fields); len(changed) > 0 {
+			torrents[hash] = changed
+		}
+	}
+	if len(torrents) > 0 {
+		response["torrents"] = torrents
+	}
+	var removed []string
+	for hash := range previous.torrents {
+		if _, ok := current.torrents[hash]; !ok {
+			removed = append(removed, hash)
+		}
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		response["torrents_removed"] = removed
+	}
+
+	categories := map[string]Category{}
+	for name, category := range current.categories {
+		if old, ok := previous.categories[name]; !ok || old != category {
+			categories[name] = category
+		}
+	}
+	if len(categories) > 0 {
+		response["categories"] = categories
+	}
+	var categoriesRemoved []string
+	for name := range previous.categories {
+		if _, ok := current.categories[name]; !ok {
+			categoriesRemoved = append(categoriesRemoved, name)
+		}
+	}
+	if len(categoriesRemoved) > 0 {
+		sort.Strings(categoriesRemoved)
+		response["categories_removed"] = categoriesRemoved
+	}
+
+	if added := missing(current.tags, previous.tags); len(added) > 0 {
+		response["tags"] = added
+	}
+	if removed := missing(previous.tags, current.tags); len(removed) > 0 {
+		response["tags_removed"] = removed
+	}
+
+	if changed := diffFields(previous.serverState, current.serverState); len(changed) > 0 {
+		response["server_state"] = changed
+	}
+}
+
+func diffFields(previous, current map[string]any) map[string]any {
+	changed := map[string]any{}
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+// missing returns the elements of a that are not in b.
+func missing(a, b []string) []string {
+	in := make(map[string]bool, len(b))
+	for _, v := range b {
+		in[v] = true
+	}
+	var out []string
+	for _, v := range a {
+		if !in[v] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
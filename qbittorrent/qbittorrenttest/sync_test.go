@@ -0,0 +1,71 @@
+package qbittorrenttest_test
+
+import (
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent"
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestSyncMainData(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	defer server.Close()
+
+	client, err := qbittorrent.NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.Login(qbittorrenttest.DefaultUsername, qbittorrenttest.DefaultPassword); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	err = client.AddNewTorrent([]string{"magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=first"}, &qbittorrent.AddTorrentOptions{
+		Category: "linux",
+		Tags:     []string{"iso"},
+	})
+	if err != nil {
+		t.Fatalf("AddNewTorrent failed: %v", err)
+	}
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "89abcdef0123456789abcdef0123456789abcdef", Name: "second"})
+
+	syncer := qbittorrent.NewSyncer(client)
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if n := len(syncer.Torrents()); n != 2 {
+		t.Fatalf("expected 2 torrents after the full update, got %d", n)
+	}
+	first, ok := syncer.Torrent("0123456789abcdef0123456789abcdef01234567")
+	if !ok || first.Name != "first" || first.Category != "linux" || len(first.Tags) != 1 || first.Tags[0] != "iso" {
+		t.Fatalf("unexpected torrent: %+v", first)
+	}
+
+	server.UpdateTorrent(first.Hash, func(torrent *qbittorrenttest.Torrent) {
+		torrent.Progress = 0.5
+	})
+	server.RemoveTorrent("89abcdef0123456789abcdef0123456789abcdef")
+
+	data, err := client.GetMainData(syncer.Rid())
+	if err != nil {
+		t.Fatalf("GetMainData failed: %v", err)
+	}
+	if data.FullUpdate {
+		t.Fatal("expected an incremental update")
+	}
+	if len(data.Torrents) != 1 || string(data.Torrents[first.Hash]) != `{"progress":0.5}` {
+		t.Errorf("expected only the changed field, got %s", data.Torrents[first.Hash])
+	}
+	if len(data.TorrentsRemoved) != 1 || data.TorrentsRemoved[0] != "89abcdef0123456789abcdef0123456789abcdef" {
+		t.Errorf("unexpected torrents_removed: %v", data.TorrentsRemoved)
+	}
+
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if torrent, _ := syncer.Torrent(first.Hash); torrent.Progress != 0.5 || torrent.Name != "first" {
+		t.Errorf("incremental update was not merged: %+v", torrent)
+	}
+	if n := len(syncer.Torrents()); n != 1 {
+		t.Errorf("expected 1 torrent after removal, got %d", n)
+	}
+}
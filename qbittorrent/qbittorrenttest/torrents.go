@@ -0,0 +1,1056 @@
+package qbittorrenttest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Torrent is the fake's view of a torrent. The exported JSON fields are
+// served by torrents/info and sync/maindata; the remaining fields back the
+// per-torrent endpoints.
+type Torrent struct {
+	Hash             string   `json:"hash"`
+	InfohashV1       string   `json:"infohash_v1"`
+	InfohashV2       string   `json:"infohash_v2"`
+	Name             string   `json:"name"`
+	MagnetURI        string   `json:"magnet_uri"`
+	State            string   `json:"state"`
+	Progress         float64  `json:"progress"`
+	Size             int64    `json:"size"`
+	TotalSize        int64    `json:"total_size"`
+	Downloaded       int64    `json:"downloaded"`
+	Uploaded         int64    `json:"uploaded"`
+	AmountLeft       int64    `json:"amount_left"`
+	DlSpeed          int64    `json:"dlspeed"`
+	UpSpeed          int64    `json:"upspeed"`
+	DlLimit          int64    `json:"dl_limit"`
+	UpLimit          int64    `json:"up_limit"`
+	Ratio            float64  `json:"ratio"`
+	RatioLimit       float64  `json:"ratio_limit"`
+	SeedingTimeLimit int64    `json:"seeding_time_limit"`
+	Category         string   `json:"category"`
+	Tags             []string `json:"-"`
+	SavePath         string   `json:"save_path"`
+	ContentPath      string   `json:"content_path"`
+	Tracker          string   `json:"tracker"`
+	Priority         int      `json:"priority"`
+	AddedOn          int64    `json:"added_on"`
+	CompletionOn     int64    `json:"completion_on"`
+	ETA              int64    `json:"eta"`
+	NumSeeds         int      `json:"num_seeds"`
+	NumLeechs        int      `json:"num_leechs"`
+	SeqDl            bool     `json:"seq_dl"`
+	FLPiecePrio      bool     `json:"f_l_piece_prio"`
+	ForceStart       bool     `json:"force_start"`
+	SuperSeeding     bool     `json:"super_seeding"`
+	AutoTMM          bool     `json:"auto_tmm"`
+
+	// Peers is keyed by "ip:port", as in sync/torrentPeers.
+	Peers       map[string]Peer `json:"-"`
+	Trackers    []Tracker       `json:"-"`
+	WebSeeds    []string        `json:"-"`
+	Files       []File          `json:"-"`
+	PieceSize   int64           `json:"-"`
+	PieceStates []int           `json:"-"`
+	PieceHashes []string        `json:"-"`
+}
+
+func (t Torrent) MarshalJSON() ([]byte, error) {
+	type plain Torrent
+	return json.Marshal(struct {
+		plain
+		Tags string `json:"tags"`
+	}{plain(t), strings.Join(t.Tags, ", ")})
+}
+
+type Peer struct {
+	IP          string  `json:"ip"`
+	Port        int     `json:"port"`
+	Client      string  `json:"client"`
+	Country     string  `json:"country"`
+	CountryCode string  `json:"country_code"`
+	Connection  string  `json:"connection"`
+	Flags       string  `json:"flags"`
+	FlagsDesc   string  `json:"flags_desc"`
+	Progress    float64 `json:"progress"`
+	DlSpeed     int64   `json:"dl_speed"`
+	UpSpeed     int64   `json:"up_speed"`
+	Downloaded  int64   `json:"downloaded"`
+	Uploaded    int64   `json:"uploaded"`
+	Relevance   float64 `json:"relevance"`
+	Files       string  `json:"files"`
+}
+
+type Tracker struct {
+	URL           string `json:"url"`
+	Status        int    `json:"status"`
+	Tier          int    `json:"tier"`
+	NumPeers      int    `json:"num_peers"`
+	NumSeeds      int    `json:"num_seeds"`
+	NumLeeches    int    `json:"num_leeches"`
+	NumDownloaded int    `json:"num_downloaded"`
+	Msg           string `json:"msg"`
+}
+
+type File struct {
+	Index        int     `json:"index"`
+	Name         string  `json:"name"`
+	Size         int64   `json:"size"`
+	Progress     float64 `json:"progress"`
+	Priority     int     `json:"priority"`
+	IsSeed       bool    `json:"is_seed"`
+	PieceRange   [2]int  `json:"piece_range"`
+	Availability float64 `json:"availability"`
+}
+
+type Category struct {
+	Name     string `json:"name"`
+	SavePath string `json:"savePath"`
+}
+
+// AddTorrent adds t to the server as if it had been added by a client.
+// Hash is required; State and AddedOn get defaults when empty.
+func (s *Server) AddTorrent(t Torrent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addTorrent(&t)
+}
+
+// UpdateTorrent calls fn with the stored torrent so tests can simulate
+// progress. It reports whether the torrent exists.
+func (s *Server) UpdateTorrent(hash string, fn func(*Torrent)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	torrent, ok := s.torrents[hash]
+	if ok {
+		fn(torrent)
+		if torrent.Category != "" {
+			s.ensureCategory(torrent.Category)
+		}
+		for _, tag := range torrent.Tags {
+			s.tags[tag] = true
+		}
+	}
+	return ok
+}
+
+// RemoveTorrent deletes a torrent as if it had been removed by a client.
+func (s *Server) RemoveTorrent(hash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeTorrent(hash)
+}
+
+func (s *Server) Torrent(hash string) (Torrent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	torrent, ok := s.torrents[hash]
+	if !ok {
+		return Torrent{}, false
+	}
+	return *torrent, true
+}
+
+// Torrents returns every torrent in the order they were added.
+func (s *Server) Torrents() []Torrent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	torrents := make([]Torrent, 0, len(s.order))
+	for _, hash := range s.order {
+		torrents = append(torrents, *s.torrents[hash])
+	}
+	return torrents
+}
+
+func (s *Server) addTorrent(t *Torrent) {
+	if t.State == "" {
+		t.State = "stalledDL"
+		if t.Progress >= 1 {
+			t.State = "stalledUP"
+		}
+	}
+	if t.AddedOn == 0 {
+		t.AddedOn = time.Now().Unix()
+	}
+	if t.SavePath == "" {
+		t.SavePath, _ = s.preferences["save_path"].(string)
+	}
+	if t.InfohashV1 == "" && len(t.Hash) == 40 {
+		t.InfohashV1 = t.Hash
+	}
+	if t.ContentPath == "" {
+		t.ContentPath = path.Join(t.SavePath, t.Name)
+	}
+	if t.Category != "" {
+		s.ensureCategory(t.Category)
+	}
+	for _, tag := range t.Tags {
+		s.tags[tag] = true
+	}
+
+	if _, ok := s.torrents[t.Hash]; !ok {
+		s.order = append(s.order, t.Hash)
+	}
+	s.torrents[t.Hash] = t
+	s.renumberQueue()
+}
+
+func (s *Server) removeTorrent(hash string) {
+	if _, ok := s.torrents[hash]; !ok {
+		return
+	}
+	delete(s.torrents, hash)
+	delete(s.peerSnapshots, hash)
+	for i, h := range s.order {
+		if h == hash {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	s.renumberQueue()
+}
+
+func (s *Server) ensureCategory(name string) {
+	if _, ok := s.categories[name]; !ok {
+		s.categories[name] = Category{Name: name}
+	}
+}
+
+// renumberQueue gives unfinished torrents queue positions in s.order and
+// finished ones priority 0, like a server with queueing enabled.
+func (s *Server) renumberQueue() {
+	position := 0
+	for _, hash := range s.order {
+		torrent := s.torrents[hash]
+		if torrent.Progress >= 1 {
+			torrent.Priority = 0
+			continue
+		}
+		position++
+		torrent.Priority = position
+	}
+}
+
+// requestedTorrents resolves a "|" separated hashes value, where "all"
+// selects every torrent. Unknown hashes are ignored like on a real server.
+func (s *Server) requestedTorrents(hashes string) []*Torrent {
+	var torrents []*Torrent
+	if hashes == "all" {
+		for _, hash := range s.order {
+			torrents = append(torrents, s.torrents[hash])
+		}
+		return torrents
+	}
+	for _, hash := range strings.Split(hashes, "|") {
+		if torrent, ok := s.torrents[strings.ToLower(hash)]; ok {
+			torrents = append(torrents, torrent)
+		}
+	}
+	return torrents
+}
+
+// torrentHandler wraps handlers of single-torrent endpoints, answering 404
+// when the hash is unknown.
+func (s *Server) torrentHandler(fn func(http.ResponseWriter, *http.Request, *Torrent)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		torrent, ok := s.torrents[strings.ToLower(r.FormValue("hash"))]
+		if !ok {
+			http.Error(w, "Torrent hash was not found", http.StatusNotFound)
+			return
+		}
+		fn(w, r, torrent)
+	}
+}
+
+// eachTorrent wraps handlers that apply to every torrent in "hashes".
+func (s *Server) eachTorrent(fn func(*http.Request, *Torrent)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			fn(r, torrent)
+		}
+	}
+}
+
+func (s *Server) pausedState(t *Torrent) string {
+	prefix := "paused"
+	if s.apiAtLeast(2, 11, 0) {
+		prefix = "stopped"
+	}
+	if t.Progress >= 1 {
+		return prefix + "UP"
+	}
+	return prefix + "DL"
+}
+
+func resumedState(t *Torrent) string {
+	if t.Progress >= 1 {
+		return "stalledUP"
+	}
+	return "stalledDL"
+}
+
+func isPausedState(state string) bool {
+	return strings.HasPrefix(state, "paused") || strings.HasPrefix(state, "stopped")
+}
+
+// apiAtLeast compares the configured Web API version.
+func (s *Server) apiAtLeast(major, minor, patch int) bool {
+	var v [3]int
+	for i, part := range strings.SplitN(s.apiVersion, ".", 3) {
+		v[i], _ = strconv.Atoi(part)
+	}
+	want := [3]int{major, minor, patch}
+	for i := range v {
+		if v[i] != want[i] {
+			return v[i] > want[i]
+		}
+	}
+	return true
+}
+
+func (s *Server) routeTorrents(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/torrents/info", s.torrentsInfo)
+	s.handle(mux, "/api/v2/torrents/add", s.torrentsAdd)
+
+	s.handle(mux, "/api/v2/torrents/properties", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		writeJSON(w, s.properties(t))
+	}))
+	s.handle(mux, "/api/v2/torrents/trackers", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		trackers := []Tracker{
+			{URL: "** [DHT] **", Status: 2, Tier: -1},
+			{URL: "** [PeX] **", Status: 2, Tier: -1},
+			{URL: "** [LSD] **", Status: 2, Tier: -1},
+		}
+		writeJSON(w, append(trackers, t.Trackers...))
+	}))
+	s.handle(mux, "/api/v2/torrents/webseeds", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		seeds := []map[string]string{}
+		for _, seed := range t.WebSeeds {
+			seeds = append(seeds, map[string]string{"url": seed})
+		}
+		writeJSON(w, seeds)
+	}))
+	s.handle(mux, "/api/v2/torrents/addWebSeeds", s.sinceAPI(2, 11, 3, s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		urls := strings.Split(r.FormValue("urls"), "|")
+		for _, u := range urls {
+			if !validURL(u) {
+				http.Error(w, "URL is not valid: "+u, http.StatusBadRequest)
+				return
+			}
+		}
+		for _, u := range urls {
+			if !contains(t.WebSeeds, u) {
+				t.WebSeeds = append(t.WebSeeds, u)
+			}
+		}
+	})))
+	s.handle(mux, "/api/v2/torrents/editWebSeed", s.sinceAPI(2, 11, 3, s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		origURL, newURL := r.FormValue("origUrl"), r.FormValue("newUrl")
+		if !validURL(newURL) {
+			http.Error(w, "New URL is not valid", http.StatusBadRequest)
+			return
+		}
+		for i, seed := range t.WebSeeds {
+			if seed == origURL {
+				t.WebSeeds[i] = newURL
+				return
+			}
+		}
+		http.Error(w, "Web seed was not found", http.StatusConflict)
+	})))
+	s.handle(mux, "/api/v2/torrents/removeWebSeeds", s.sinceAPI(2, 11, 3, s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		urls := strings.Split(r.FormValue("urls"), "|")
+		var kept []string
+		for _, seed := range t.WebSeeds {
+			if !contains(urls, seed) {
+				kept = append(kept, seed)
+			}
+		}
+		t.WebSeeds = kept
+	})))
+	s.handle(mux, "/api/v2/torrents/files", s.torrentHandler(s.torrentFiles))
+	s.handle(mux, "/api/v2/torrents/pieceStates", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		writeJSON(w, nonNil(t.PieceStates))
+	}))
+	s.handle(mux, "/api/v2/torrents/pieceHashes", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		writeJSON(w, nonNil(t.PieceHashes))
+	}))
+
+	// qBittorrent 5.0 (Web API 2.11.0) renamed pause/resume to stop/start.
+	pause := s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.State = s.pausedState(t)
+	})
+	resume := s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.State = resumedState(t)
+	})
+	s.handle(mux, "/api/v2/torrents/pause", s.untilAPI(2, 11, 0, pause))
+	s.handle(mux, "/api/v2/torrents/resume", s.untilAPI(2, 11, 0, resume))
+	s.handle(mux, "/api/v2/torrents/stop", s.sinceAPI(2, 11, 0, pause))
+	s.handle(mux, "/api/v2/torrents/start", s.sinceAPI(2, 11, 0, resume))
+
+	s.handle(mux, "/api/v2/torrents/delete", func(w http.ResponseWriter, r *http.Request) {
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			s.removeTorrent(torrent.Hash)
+		}
+	})
+	s.handle(mux, "/api/v2/torrents/recheck", s.eachTorrent(func(r *http.Request, t *Torrent) {}))
+	s.handle(mux, "/api/v2/torrents/reannounce", s.eachTorrent(func(r *http.Request, t *Torrent) {}))
+
+	s.handle(mux, "/api/v2/torrents/editTracker", s.torrentHandler(s.editTracker))
+	s.handle(mux, "/api/v2/torrents/removeTrackers", s.torrentHandler(s.removeTrackers))
+	s.handle(mux, "/api/v2/torrents/addTrackers", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		for _, u := range strings.Split(r.FormValue("urls"), "\n") {
+			if u = strings.TrimSpace(u); u != "" && trackerIndex(t, u) < 0 {
+				t.Trackers = append(t.Trackers, Tracker{URL: u, Status: 1})
+			}
+		}
+	}))
+	s.handle(mux, "/api/v2/torrents/addPeers", func(w http.ResponseWriter, r *http.Request) {
+		if len(s.requestedTorrents(r.FormValue("hashes"))) == 0 || r.FormValue("peers") == "" {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+		}
+	})
+
+	s.handle(mux, "/api/v2/torrents/increasePrio", s.queueHandler(func(i int) int { return i - 1 }))
+	s.handle(mux, "/api/v2/torrents/decreasePrio", s.queueHandler(func(i int) int { return i + 1 }))
+	s.handle(mux, "/api/v2/torrents/topPrio", s.queueHandler(func(int) int { return -1 }))
+	s.handle(mux, "/api/v2/torrents/bottomPrio", s.queueHandler(func(int) int { return len(s.order) }))
+
+	s.handle(mux, "/api/v2/torrents/filePrio", s.torrentHandler(s.filePrio))
+	s.handle(mux, "/api/v2/torrents/downloadLimit", func(w http.ResponseWriter, r *http.Request) {
+		limits := map[string]int64{}
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			limits[torrent.Hash] = torrent.DlLimit
+		}
+		writeJSON(w, limits)
+	})
+	s.handle(mux, "/api/v2/torrents/uploadLimit", func(w http.ResponseWriter, r *http.Request) {
+		limits := map[string]int64{}
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			limits[torrent.Hash] = torrent.UpLimit
+		}
+		writeJSON(w, limits)
+	})
+	s.handle(mux, "/api/v2/torrents/setDownloadLimit", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.DlLimit, _ = strconv.ParseInt(r.FormValue("limit"), 10, 64)
+	}))
+	s.handle(mux, "/api/v2/torrents/setUploadLimit", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.UpLimit, _ = strconv.ParseInt(r.FormValue("limit"), 10, 64)
+	}))
+	s.handle(mux, "/api/v2/torrents/setShareLimits", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.RatioLimit, _ = strconv.ParseFloat(r.FormValue("ratioLimit"), 64)
+		t.SeedingTimeLimit, _ = strconv.ParseInt(r.FormValue("seedingTimeLimit"), 10, 64)
+	}))
+
+	s.handle(mux, "/api/v2/torrents/setLocation", func(w http.ResponseWriter, r *http.Request) {
+		location := r.FormValue("location")
+		if location == "" {
+			http.Error(w, "Save path is empty", http.StatusBadRequest)
+			return
+		}
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			torrent.SavePath = location
+			torrent.ContentPath = path.Join(location, torrent.Name)
+		}
+	})
+	s.handle(mux, "/api/v2/torrents/rename", s.torrentHandler(func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		name := strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			http.Error(w, "Incorrect torrent name", http.StatusConflict)
+			return
+		}
+		t.Name = name
+	}))
+	s.handle(mux, "/api/v2/torrents/setCategory", func(w http.ResponseWriter, r *http.Request) {
+		category := r.FormValue("category")
+		if _, ok := s.categories[category]; category != "" && !ok {
+			http.Error(w, "Incorrect category name", http.StatusConflict)
+			return
+		}
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			torrent.Category = category
+		}
+	})
+
+	s.routeCategories(mux)
+	s.routeTags(mux)
+
+	s.handle(mux, "/api/v2/torrents/setAutoManagement", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.AutoTMM = r.FormValue("enable") == "true"
+	}))
+	s.handle(mux, "/api/v2/torrents/toggleSequentialDownload", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.SeqDl = !t.SeqDl
+	}))
+	s.handle(mux, "/api/v2/torrents/toggleFirstLastPiecePrio", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.FLPiecePrio = !t.FLPiecePrio
+	}))
+	s.handle(mux, "/api/v2/torrents/setForceStart", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.ForceStart = r.FormValue("value") == "true"
+	}))
+	s.handle(mux, "/api/v2/torrents/setSuperSeeding", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		t.SuperSeeding = r.FormValue("value") == "true"
+	}))
+	s.handle(mux, "/api/v2/torrents/renameFile", s.torrentHandler(s.renamePath(false)))
+	s.handle(mux, "/api/v2/torrents/renameFolder", s.torrentHandler(s.renamePath(true)))
+}
+
+// sinceAPI serves fn only on Web API major.minor.patch and later, answering
+// 404 like an older server that does not know the endpoint.
+func (s *Server) sinceAPI(major, minor, patch int, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.apiAtLeast(major, minor, patch) {
+			http.NotFound(w, r)
+			return
+		}
+		fn(w, r)
+	}
+}
+
+// untilAPI serves fn only before Web API major.minor.patch.
+func (s *Server) untilAPI(major, minor, patch int, fn http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.apiAtLeast(major, minor, patch) {
+			http.NotFound(w, r)
+			return
+		}
+		fn(w, r)
+	}
+}
+
+func validURL(u string) bool {
+	parsed, err := url.Parse(u)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
+
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+func (s *Server) torrentsInfo(w http.ResponseWriter, r *http.Request) {
+	var hashes map[string]bool
+	if value := r.FormValue("hashes"); value != "" {
+		hashes = map[string]bool{}
+		for _, hash := range strings.Split(value, "|") {
+			hashes[strings.ToLower(hash)] = true
+		}
+	}
+
+	torrents := []*Torrent{}
+	for _, hash := range s.order {
+		torrent := s.torrents[hash]
+		if hashes != nil && !hashes[hash] {
+			continue
+		}
+		if !matchesFilter(torrent, r.FormValue("filter")) {
+			continue
+		}
+		if _, ok := r.Form["category"]; ok && torrent.Category != r.FormValue("category") {
+			continue
+		}
+		if _, ok := r.Form["tag"]; ok && !hasTag(torrent, r.FormValue("tag")) {
+			continue
+		}
+		torrents = append(torrents, torrent)
+	}
+
+	if key := r.FormValue("sort"); key != "" {
+		values := make(map[*Torrent]any, len(torrents))
+		for _, torrent := range torrents {
+			var fields map[string]any
+			data, _ := json.Marshal(torrent)
+			json.Unmarshal(data, &fields)
+			values[torrent] = fields[key]
+		}
+		sort.SliceStable(torrents, func(i, j int) bool {
+			return less(values[torrents[i]], values[torrents[j]])
+		})
+	}
+	if r.FormValue("reverse") == "true" {
+		for i, j := 0, len(torrents)-1; i < j; i, j = i+1, j-1 {
+			torrents[i], torrents[j] = torrents[j], torrents[i]
+		}
+	}
+
+	offset, _ := formInt(r, "offset", 0)
+	if offset < 0 {
+		offset += len(torrents)
+	}
+	offset = max(0, min(offset, len(torrents)))
+	torrents = torrents[offset:]
+	if limit, _ := formInt(r, "limit", 0); limit > 0 && limit < len(torrents) {
+		torrents = torrents[:limit]
+	}
+
+	writeJSON(w, torrents)
+}
+
+func less(a, b any) bool {
+	switch a := a.(type) {
+	case float64:
+		b, _ := b.(float64)
+		return a < b
+	case string:
+		b, _ := b.(string)
+		return a < b
+	case bool:
+		b, _ := b.(bool)
+		return !a && b
+	}
+	return false
+}
+
+func hasTag(t *Torrent, tag string) bool {
+	if tag == "" {
+		return len(t.Tags) == 0
+	}
+	for _, have := range t.Tags {
+		if have == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesFilter(t *Torrent, filter string) bool {
+	paused := isPausedState(t.State)
+	active := t.DlSpeed > 0 || t.UpSpeed > 0
+	switch filter {
+	case "", "all":
+		return true
+	case "downloading":
+		return t.Progress < 1 && !paused
+	case "seeding":
+		return t.Progress >= 1 && !paused
+	case "completed":
+		return t.Progress >= 1
+	case "paused", "stopped":
+		return paused
+	case "resumed", "running":
+		return !paused
+	case "active":
+		return active
+	case "inactive":
+		return !active
+	case "stalled":
+		return t.State == "stalledDL" || t.State == "stalledUP"
+	case "stalled_uploading":
+		return t.State == "stalledUP"
+	case "stalled_downloading":
+		return t.State == "stalledDL"
+	case "errored":
+		return t.State == "error" || t.State == "missingFiles"
+	}
+	return false
+}
+
+func (s *Server) torrentsAdd(w http.ResponseWriter, r *http.Request) {
+	var added []*Torrent
+	for _, u := range strings.Split(r.FormValue("urls"), "\n") {
+		if u = strings.TrimSpace(u); u != "" {
+			added = append(added, torrentFromURL(u))
+		}
+	}
+	if r.MultipartForm != nil {
+		for _, header := range r.MultipartForm.File["torrents"] {
+			f, err := header.Open()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			sum := sha1.Sum(data)
+			added = append(added, &Torrent{
+				Hash: hex.EncodeToString(sum[:]),
+				Name: strings.TrimSuffix(header.Filename, ".torrent"),
+			})
+		}
+	}
+
+	count := 0
+	for _, torrent := range added {
+		if _, ok := s.torrents[torrent.Hash]; ok {
+			continue
+		}
+		applyAddOptions(r, torrent)
+		if torrent.State == "" && (r.FormValue("paused") == "true" || r.FormValue("stopped") == "true") {
+			torrent.State = s.pausedState(torrent)
+		}
+		s.addTorrent(torrent)
+		count++
+	}
+
+	if count == 0 {
+		writeText(w, "Fails.")
+		return
+	}
+	writeText(w, "Ok.")
+}
+
+func torrentFromURL(u string) *Torrent {
+	torrent := &Torrent{MagnetURI: u}
+	if parsed, err := url.Parse(u); err == nil && parsed.Scheme == "magnet" {
+		query := parsed.Query()
+		torrent.Name = query.Get("dn")
+		for _, xt := range query["xt"] {
+			if hash, ok := strings.CutPrefix(xt, "urn:btih:"); ok {
+				torrent.Hash = strings.ToLower(hash)
+			}
+		}
+	} else if err == nil {
+		torrent.MagnetURI = ""
+		torrent.Name = strings.TrimSuffix(path.Base(parsed.Path), ".torrent")
+	}
+	if torrent.Hash == "" {
+		sum := sha1.Sum([]byte(u))
+		torrent.Hash = hex.EncodeToString(sum[:])
+	}
+	if torrent.Name == "" {
+		torrent.Name = torrent.Hash
+	}
+	return torrent
+}
+
+func applyAddOptions(r *http.Request, t *Torrent) {
+	if value := r.FormValue("savepath"); value != "" {
+		t.SavePath = value
+	}
+	if value := r.FormValue("rename"); value != "" {
+		t.Name = value
+	}
+	t.Category = r.FormValue("category")
+	if value := r.FormValue("tags"); value != "" {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	}
+	if r.FormValue("skip_checking") == "true" {
+		t.Progress = 1
+	}
+	t.UpLimit, _ = strconv.ParseInt(r.FormValue("upLimit"), 10, 64)
+	t.DlLimit, _ = strconv.ParseInt(r.FormValue("dlLimit"), 10, 64)
+	t.RatioLimit, _ = strconv.ParseFloat(r.FormValue("ratioLimit"), 64)
+	t.SeedingTimeLimit, _ = strconv.ParseInt(r.FormValue("seedingTimeLimit"), 10, 64)
+	t.AutoTMM = r.FormValue("autoTMM") == "true"
+	t.SeqDl = r.FormValue("sequentialDownload") == "true"
+	t.FLPiecePrio = r.FormValue("firstLastPiecePrio") == "true"
+}
+
+func (s *Server) properties(t *Torrent) map[string]any {
+	completionDate := int64(-1)
+	if t.CompletionOn > 0 {
+		completionDate = t.CompletionOn
+	}
+	return map[string]any{
+		"hash":                     t.Hash,
+		"infohash_v1":              t.InfohashV1,
+		"infohash_v2":              t.InfohashV2,
+		"name":                     t.Name,
+		"save_path":                t.SavePath,
+		"creation_date":            t.AddedOn,
+		"piece_size":               t.PieceSize,
+		"comment":                  "",
+		"created_by":               "",
+		"is_private":               false,
+		"download_path":            "",
+		"total_wasted":             0,
+		"total_uploaded":           t.Uploaded,
+		"total_uploaded_session":   t.Uploaded,
+		"total_downloaded":         t.Downloaded,
+		"total_downloaded_session": t.Downloaded,
+		"up_limit":                 limitOrUnlimited(t.UpLimit),
+		"dl_limit":                 limitOrUnlimited(t.DlLimit),
+		"time_elapsed":             time.Now().Unix() - t.AddedOn,
+		"seeding_time":             0,
+		"nb_connections":           0,
+		"nb_connections_limit":     100,
+		"share_ratio":              t.Ratio,
+		"addition_date":            t.AddedOn,
+		"completion_date":          completionDate,
+		"dl_speed":                 t.DlSpeed,
+		"dl_speed_avg":             t.DlSpeed,
+		"up_speed":                 t.UpSpeed,
+		"up_speed_avg":             t.UpSpeed,
+		"eta":                      t.ETA,
+		"last_seen":                -1,
+		"peers":                    t.NumLeechs,
+		"peers_total":              t.NumLeechs,
+		"seeds":                    t.NumSeeds,
+		"seeds_total":              t.NumSeeds,
+		"pieces_have":              countPieces(t.PieceStates, 2),
+		"pieces_num":               len(t.PieceStates),
+		"reannounce":               0,
+		"total_size":               t.TotalSize,
+	}
+}
+
+// limitOrUnlimited reports a zero limit as -1, as torrents/properties does.
+func limitOrUnlimited(limit int64) int64 {
+	if limit <= 0 {
+		return -1
+	}
+	return limit
+}
+
+func countPieces(states []int, state int) int {
+	n := 0
+	for _, s := range states {
+		if s == state {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *Server) torrentFiles(w http.ResponseWriter, r *http.Request, t *Torrent) {
+	files := nonNil(t.Files)
+	if value := r.FormValue("indexes"); value != "" {
+		wanted := map[int]bool{}
+		for _, index := range strings.Split(value, "|") {
+			i, err := strconv.Atoi(index)
+			if err != nil {
+				http.Error(w, "Bad Request", http.StatusBadRequest)
+				return
+			}
+			wanted[i] = true
+		}
+		files = []File{}
+		for _, file := range t.Files {
+			if wanted[file.Index] {
+				files = append(files, file)
+			}
+		}
+	}
+	writeJSON(w, files)
+}
+
+func (s *Server) filePrio(w http.ResponseWriter, r *http.Request, t *Torrent) {
+	priority, err := strconv.Atoi(r.FormValue("priority"))
+	if err != nil || (priority != 0 && priority != 1 && priority != 6 && priority != 7) {
+		http.Error(w, "Priority is not valid", http.StatusBadRequest)
+		return
+	}
+
+	var indexes []int
+	for _, id := range strings.Split(r.FormValue("id"), "|") {
+		index, err := strconv.Atoi(id)
+		if err != nil {
+			http.Error(w, "File IDs are not valid", http.StatusBadRequest)
+			return
+		}
+		if index < 0 || index >= len(t.Files) {
+			http.Error(w, "File ID is not valid", http.StatusConflict)
+			return
+		}
+		indexes = append(indexes, index)
+	}
+	for _, index := range indexes {
+		t.Files[index].Priority = priority
+	}
+}
+
+func trackerIndex(t *Torrent, u string) int {
+	for i, tracker := range t.Trackers {
+		if tracker.URL == u {
+			return i
+		}
+	}
+	return -1
+}
+
+func (s *Server) editTracker(w http.ResponseWriter, r *http.Request, t *Torrent) {
+	origURL, newURL := r.FormValue("origUrl"), r.FormValue("newUrl")
+	if !validURL(newURL) {
+		http.Error(w, "New tracker URL is invalid", http.StatusBadRequest)
+		return
+	}
+	i := trackerIndex(t, origURL)
+	if i < 0 || trackerIndex(t, newURL) >= 0 {
+		http.Error(w, "Tracker not found or new URL already exists", http.StatusConflict)
+		return
+	}
+	t.Trackers[i].URL = newURL
+	if t.Tracker == origURL {
+		t.Tracker = newURL
+	}
+}
+
+func (s *Server) removeTrackers(w http.ResponseWriter, r *http.Request, t *Torrent) {
+	removed := 0
+	for _, u := range strings.Split(r.FormValue("urls"), "|") {
+		if i := trackerIndex(t, u); i >= 0 {
+			t.Trackers = append(t.Trackers[:i], t.Trackers[i+1:]...)
+			removed++
+		}
+	}
+	if removed == 0 {
+		http.Error(w, "No trackers were removed", http.StatusConflict)
+	}
+}
+
+// queueHandler moves the requested torrents to the queue position returned
+// by move, given their current index in s.order.
+func (s *Server) queueHandler(move func(int) int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if enabled, _ := s.preferences["queueing_enabled"].(bool); !enabled {
+			http.Error(w, "Torrent queueing must be enabled", http.StatusConflict)
+			return
+		}
+		for _, torrent := range s.requestedTorrents(r.FormValue("hashes")) {
+			for i, hash := range s.order {
+				if hash != torrent.Hash {
+					continue
+				}
+				s.order = append(s.order[:i], s.order[i+1:]...)
+				to := max(0, min(move(i), len(s.order)))
+				s.order = append(s.order[:to], append([]string{hash}, s.order[to:]...)...)
+				break
+			}
+		}
+		s.renumberQueue()
+	}
+}
+
+func (s *Server) renamePath(folder bool) func(http.ResponseWriter, *http.Request, *Torrent) {
+	return func(w http.ResponseWriter, r *http.Request, t *Torrent) {
+		oldPath, newPath := r.FormValue("oldPath"), r.FormValue("newPath")
+		if newPath == "" {
+			http.Error(w, "New path is empty", http.StatusBadRequest)
+			return
+		}
+		renamed := false
+		for i := range t.Files {
+			name := t.Files[i].Name
+			switch {
+			case !folder && name == oldPath:
+				t.Files[i].Name = newPath
+				renamed = true
+			case folder && strings.HasPrefix(name, oldPath+"/"):
+				t.Files[i].Name = newPath + strings.TrimPrefix(name, oldPath)
+				renamed = true
+			}
+		}
+		if !renamed {
+			http.Error(w, "Path not found", http.StatusConflict)
+		}
+	}
+}
+
+func (s *Server) routeCategories(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/torrents/categories", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, s.categories)
+	})
+	s.handle(mux, "/api/v2/torrents/createCategory", func(w http.ResponseWriter, r *http.Request) {
+		name := r.FormValue("category")
+		if name == "" {
+			http.Error(w, "Category name is empty", http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.categories[name]; ok {
+			http.Error(w, "Category already exists", http.StatusConflict)
+			return
+		}
+		s.categories[name] = Category{Name: name, SavePath: r.FormValue("savePath")}
+	})
+	s.handle(mux, "/api/v2/torrents/editCategory", func(w http.ResponseWriter, r *http.Request) {
+		name := r.FormValue("category")
+		if name == "" {
+			http.Error(w, "Category name is empty", http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.categories[name]; !ok {
+			http.Error(w, "Category does not exist", http.StatusConflict)
+			return
+		}
+		s.categories[name] = Category{Name: name, SavePath: r.FormValue("savePath")}
+	})
+	s.handle(mux, "/api/v2/torrents/removeCategories", func(w http.ResponseWriter, r *http.Request) {
+		for _, name := range strings.Split(r.FormValue("categories"), "\n") {
+			delete(s.categories, name)
+			for _, torrent := range s.torrents {
+				if torrent.Category == name {
+					torrent.Category = ""
+				}
+			}
+		}
+	})
+}
+
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (s *Server) routeTags(mux *http.ServeMux) {
+	s.handle(mux, "/api/v2/torrents/tags", func(w http.ResponseWriter, r *http.Request) {
+		tags := []string{}
+		for tag := range s.tags {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		writeJSON(w, tags)
+	})
+	s.handle(mux, "/api/v2/torrents/createTags", func(w http.ResponseWriter, r *http.Request) {
+		for _, tag := range splitTags(r.FormValue("tags")) {
+			s.tags[tag] = true
+		}
+	})
+	s.handle(mux, "/api/v2/torrents/deleteTags", func(w http.ResponseWriter, r *http.Request) {
+		for _, tag := range splitTags(r.FormValue("tags")) {
+			delete(s.tags, tag)
+			for _, torrent := range s.torrents {
+				torrent.Tags = removeTag(torrent.Tags, tag)
+			}
+		}
+	})
+	s.handle(mux, "/api/v2/torrents/addTags", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		for _, tag := range splitTags(r.FormValue("tags")) {
+			s.tags[tag] = true
+			if !hasTag(t, tag) {
+				t.Tags = append(t.Tags, tag)
+			}
+		}
+	}))
+	s.handle(mux, "/api/v2/torrents/removeTags", s.eachTorrent(func(r *http.Request, t *Torrent) {
+		tags := splitTags(r.FormValue("tags"))
+		if len(tags) == 0 {
+			t.Tags = nil
+		}
+		for _, tag := range tags {
+			t.Tags = removeTag(t.Tags, tag)
+		}
+	}))
+}
+
+func removeTag(tags []string, tag string) []string {
+	var kept []string
+	for _, have := range tags {
+		if have != tag {
+			kept = append(kept, have)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,347 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MainData is a single /api/v2/sync/maindata response. Unless FullUpdate is
+// set, torrents, categories and server_state only carry the fields that
+// changed since the requested rid, so they are kept as raw JSON for the
+// Syncer to merge.
+type MainData struct {
+	Rid               int                        `json:"rid"`
+	FullUpdate        bool                       `json:"full_update"`
+	Torrents          map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved   []string                   `json:"torrents_removed"`
+	Categories        map[string]json.RawMessage `json:"categories"`
+	CategoriesRemoved []string                   `json:"categories_removed"`
+	Tags              []string                   `json:"tags"`
+	TagsRemoved       []string                   `json:"tags_removed"`
+	ServerState       json.RawMessage            `json:"server_state"`
+}
+
+type Category struct {
+	Name     string `json:"name"`
+	SavePath string `json:"savePath"`
+}
+
+// ServerState is the server_state object of sync/maindata: the transfer info
+// plus session statistics. The external addresses are only reported by
+// qBittorrent 5 and later.
+type ServerState struct {
+	TransferInfo
+	UseAltSpeedLimits     bool     `json:"use_alt_speed_limits"`
+	Queueing              bool     `json:"queueing"`
+	RefreshInterval       int      `json:"refresh_interval"`
+	AlltimeDl             ByteSize `json:"alltime_dl"`
+	AlltimeUl             ByteSize `json:"alltime_ul"`
+	TotalWastedSession    ByteSize `json:"total_wasted_session"`
+	GlobalRatio           float64  `json:"-"`
+	TotalPeerConnections  int      `json:"total_peer_connections"`
+	FreeSpaceOnDisk       ByteSize `json:"free_space_on_disk"`
+	QueuedIOJobs          int      `json:"queued_io_jobs"`
+	AverageTimeQueue      int      `json:"average_time_queue"`
+	TotalBuffersSize      ByteSize `json:"total_buffers_size"`
+	TotalQueuedSize       ByteSize `json:"total_queued_size"`
+	ReadCacheHits         float64  `json:"-"`
+	ReadCacheOverload     float64  `json:"-"`
+	WriteCacheOverload    float64  `json:"-"`
+	LastExternalAddressV4 string   `json:"last_external_address_v4"`
+	LastExternalAddressV6 string   `json:"last_external_address_v6"`
+}
+
+// UnmarshalJSON only overwrites the fields present in data, like
+// Torrent.UnmarshalJSON. The ratio and cache statistics are sent as strings.
+func (s *ServerState) UnmarshalJSON(data []byte) error {
+	type plain ServerState
+	aux := struct {
+		*plain
+		GlobalRatio        json.RawMessage `json:"global_ratio"`
+		ReadCacheHits      json.RawMessage `json:"read_cache_hits"`
+		ReadCacheOverload  json.RawMessage `json:"read_cache_overload"`
+		WriteCacheOverload json.RawMessage `json:"write_cache_overload"`
+	}{plain: (*plain)(s)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		raw json.RawMessage
+		dst *float64
+	}{
+		{aux.GlobalRatio, &s.GlobalRatio},
+		{aux.ReadCacheHits, &s.ReadCacheHits},
+		{aux.ReadCacheOverload, &s.ReadCacheOverload},
+		{aux.WriteCacheOverload, &s.WriteCacheOverload},
+	} {
+		if field.raw == nil {
+			continue
+		}
+		v, err := parseLooseFloat(field.raw)
+		if err != nil {
+			return err
+		}
+		*field.dst = v
+	}
+	return nil
+}
+
+// MainDataSnapshot is a consistent copy of the state tracked by a Syncer.
+type MainDataSnapshot struct {
+	Rid         int
+	Torrents    map[string]Torrent
+	Categories  map[string]Category
+	Tags        []string
+	ServerState ServerState
+}
+
+// Syncer keeps an in-memory copy of the server state by polling
+// sync/maindata and merging the incremental responses. It is safe for
+// concurrent use.
+type Syncer struct {
+	client *QBittorrentClient
+
+	// syncMu serializes requests so that rid only moves forward.
+	syncMu sync.Mutex
+
+	mu          sync.RWMutex
+	rid         int
+	torrents    map[string]Torrent
+	categories  map[string]Category
+	tags        map[string]struct{}
+	serverState ServerState
+
+	subMu            sync.RWMutex
+	subscribers      []subscriber
+	nextSubscriberID int
+}
+
+func NewSyncer(client *QBittorrentClient) *Syncer {
+	return &Syncer{
+		client:     client,
+		torrents:   make(map[string]Torrent),
+		categories: make(map[string]Category),
+		tags:       make(map[string]struct{}),
+	}
+}
+
+func (s *Syncer) Sync() error {
+	return s.SyncContext(context.Background())
+}
+
+// SyncContext fetches the changes since the last known rid, applies them and
+// notifies subscribers.
+func (s *Syncer) SyncContext(ctx context.Context) error {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	s.mu.RLock()
+	rid := s.rid
+	s.mu.RUnlock()
+
+	data, err := s.client.GetMainDataContext(ctx, rid)
+	if err != nil {
+		return err
+	}
+
+	events, err := s.apply(data)
+	if err != nil {
+		return err
+	}
+
+	s.dispatch(events)
+	return nil
+}
+
+// Run calls SyncContext every interval until ctx is done or a sync fails.
+// interval must be positive.
+func (s *Syncer) Run(ctx context.Context, interval time.Duration) error {
+	if err := checkInterval(interval); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.SyncContext(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkInterval rejects polling intervals that time.NewTicker would panic on.
+func checkInterval(interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid polling interval %v: must be positive", interval)
+	}
+	return nil
+}
+
+// apply merges data into the current state and returns the resulting torrent
+// events. Everything is decoded before the state is touched so that a
+// malformed response leaves the snapshot and rid unchanged.
+func (s *Syncer) apply(data *MainData) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	torrents := s.torrents
+	categories := s.categories
+	tags := s.tags
+	serverState := s.serverState
+	if data.FullUpdate {
+		torrents = make(map[string]Torrent, len(data.Torrents))
+		categories = make(map[string]Category, len(data.Categories))
+		tags = make(map[string]struct{}, len(data.Tags))
+		serverState = ServerState{}
+	}
+
+	updatedTorrents := make(map[string]Torrent, len(data.Torrents))
+	for hash, raw := range data.Torrents {
+		torrent := torrents[hash]
+		if err := json.Unmarshal(raw, &torrent); err != nil {
+			return nil, err
+		}
+		torrent.Hash = hash
+		updatedTorrents[hash] = torrent
+	}
+
+	updatedCategories := make(map[string]Category, len(data.Categories))
+	for name, raw := range data.Categories {
+		category := categories[name]
+		if err := json.Unmarshal(raw, &category); err != nil {
+			return nil, err
+		}
+		category.Name = name
+		updatedCategories[name] = category
+	}
+
+	if len(data.ServerState) > 0 {
+		if err := json.Unmarshal(data.ServerState, &serverState); err != nil {
+			return nil, err
+		}
+	}
+
+	removed := data.TorrentsRemoved
+	if data.FullUpdate {
+		removed = nil
+		for hash := range s.torrents {
+			if _, ok := updatedTorrents[hash]; !ok {
+				removed = append(removed, hash)
+			}
+		}
+	}
+	events := diffTorrents(s.torrents, updatedTorrents, removed)
+
+	for hash, torrent := range updatedTorrents {
+		torrents[hash] = torrent
+	}
+	for _, hash := range removed {
+		delete(torrents, hash)
+	}
+	for name, category := range updatedCategories {
+		categories[name] = category
+	}
+	for _, name := range data.CategoriesRemoved {
+		delete(categories, name)
+	}
+	for _, tag := range data.Tags {
+		tags[tag] = struct{}{}
+	}
+	for _, tag := range data.TagsRemoved {
+		delete(tags, tag)
+	}
+
+	s.rid = data.Rid
+	s.torrents = torrents
+	s.categories = categories
+	s.tags = tags
+	s.serverState = serverState
+	return events, nil
+}
+
+func (s *Syncer) Rid() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rid
+}
+
+func (s *Syncer) Torrent(hash string) (Torrent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	torrent, ok := s.torrents[hash]
+	return torrent, ok
+}
+
+func (s *Syncer) Torrents() map[string]Torrent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	torrents := make(map[string]Torrent, len(s.torrents))
+	for hash, torrent := range s.torrents {
+		torrents[hash] = torrent
+	}
+	return torrents
+}
+
+func (s *Syncer) Categories() map[string]Category {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	categories := make(map[string]Category, len(s.categories))
+	for name, category := range s.categories {
+		categories[name] = category
+	}
+	return categories
+}
+
+func (s *Syncer) Tags() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sortedTags()
+}
+
+func (s *Syncer) ServerState() ServerState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.serverState
+}
+
+func (s *Syncer) Snapshot() MainDataSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := MainDataSnapshot{
+		Rid:         s.rid,
+		Torrents:    make(map[string]Torrent, len(s.torrents)),
+		Categories:  make(map[string]Category, len(s.categories)),
+		Tags:        s.sortedTags(),
+		ServerState: s.serverState,
+	}
+	for hash, torrent := range s.torrents {
+		snapshot.Torrents[hash] = torrent
+	}
+	for name, category := range s.categories {
+		snapshot.Categories[name] = category
+	}
+	return snapshot
+}
+
+func (s *Syncer) sortedTags() []string {
+	tags := make([]string, 0, len(s.tags))
+	for tag := range s.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
@@ -0,0 +1,98 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSyncerAppliesIncrementalUpdates(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,
+			"torrents":{"aaa":{"name":"one","state":"downloading","progress":0.5,"category":"movies"},
+			            "bbb":{"name":"two","state":"stalledUP","progress":1}},
+			"categories":{"movies":{"name":"movies","savePath":"/data/movies"}},
+			"tags":["hd","old"],
+			"server_state":{"connection_status":"connected","dl_info_speed":100}}`,
+		"1": `{"rid":2,
+			"torrents":{"aaa":{"progress":0.75}},
+			"torrents_removed":["bbb"],
+			"tags_removed":["old"],
+			"server_state":{"dl_info_speed":200}}`,
+		"2": `{"rid":3,"full_update":true,
+			"torrents":{"ccc":{"name":"three","state":"pausedDL"}},
+			"tags":["new"]}`,
+	}
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.URL.Query().Get("rid")
+		requested = append(requested, rid)
+		w.Write([]byte(responses[rid]))
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	syncer := NewSyncer(client)
+
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("initial sync failed: %v", err)
+	}
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("incremental sync failed: %v", err)
+	}
+
+	snapshot := syncer.Snapshot()
+	if snapshot.Rid != 2 {
+		t.Errorf("rid = %d, want 2", snapshot.Rid)
+	}
+	if len(snapshot.Torrents) != 1 {
+		t.Fatalf("expected 1 torrent, got %d", len(snapshot.Torrents))
+	}
+	torrent := snapshot.Torrents["aaa"]
+	if torrent.Hash != "aaa" || torrent.Name != "one" || torrent.Progress != 0.75 || torrent.Category != "movies" {
+		t.Errorf("partial update not merged: %+v", torrent)
+	}
+	if got := snapshot.Categories["movies"].SavePath; got != "/data/movies" {
+		t.Errorf("category save path = %q", got)
+	}
+	if !reflect.DeepEqual(snapshot.Tags, []string{"hd"}) {
+		t.Errorf("tags = %q", snapshot.Tags)
+	}
+	if snapshot.ServerState.ConnectionStatus != "connected" || snapshot.ServerState.DlInfoSpeed != 200 {
+		t.Errorf("server state not merged: %+v", snapshot.ServerState)
+	}
+
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("full update sync failed: %v", err)
+	}
+	snapshot = syncer.Snapshot()
+	if _, ok := snapshot.Torrents["aaa"]; ok || len(snapshot.Torrents) != 1 {
+		t.Errorf("full update did not reset torrents: %v", snapshot.Torrents)
+	}
+	if len(snapshot.Categories) != 0 || !reflect.DeepEqual(snapshot.Tags, []string{"new"}) {
+		t.Errorf("full update did not reset categories and tags: %v %q", snapshot.Categories, snapshot.Tags)
+	}
+
+	if !reflect.DeepEqual(requested, []string{"0", "1", "2"}) {
+		t.Errorf("requested rids = %q", requested)
+	}
+}
+
+func TestSyncerRunRejectsInvalidInterval(t *testing.T) {
+	client, err := NewDefaultClient("http://127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := NewSyncer(client).Run(context.Background(), interval); err == nil {
+			t.Errorf("Run(%v) succeeded, want an error", interval)
+		}
+	}
+}
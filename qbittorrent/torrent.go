@@ -0,0 +1,300 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type TorrentState string
+
+const (
+	StateError              TorrentState = "error"
+	StateMissingFiles       TorrentState = "missingFiles"
+	StateUploading          TorrentState = "uploading"
+	StatePausedUP           TorrentState = "pausedUP"
+	StateStoppedUP          TorrentState = "stoppedUP"
+	StateQueuedUP           TorrentState = "queuedUP"
+	StateStalledUP          TorrentState = "stalledUP"
+	StateCheckingUP         TorrentState = "checkingUP"
+	StateForcedUP           TorrentState = "forcedUP"
+	StateAllocating         TorrentState = "allocating"
+	StateDownloading        TorrentState = "downloading"
+	StateMetaDL             TorrentState = "metaDL"
+	StateForcedMetaDL       TorrentState = "forcedMetaDL"
+	StatePausedDL           TorrentState = "pausedDL"
+	StateStoppedDL          TorrentState = "stoppedDL"
+	StateQueuedDL           TorrentState = "queuedDL"
+	StateStalledDL          TorrentState = "stalledDL"
+	StateCheckingDL         TorrentState = "checkingDL"
+	StateForcedDL           TorrentState = "forcedDL"
+	StateCheckingResumeData TorrentState = "checkingResumeData"
+	StateMoving             TorrentState = "moving"
+	StateUnknown            TorrentState = "unknown"
+)
+
+// IsStopped reports whether the torrent is paused, as qBittorrent 4 calls
+// it, or stopped, as qBittorrent 5 does.
+func (s TorrentState) IsStopped() bool {
+	switch s {
+	case StatePausedUP, StatePausedDL, StateStoppedUP, StateStoppedDL:
+		return true
+	}
+	return false
+}
+
+// InfiniteETA is the ETA the server reports when a torrent is not expected
+// to finish, e.g. because it has no peers.
+const InfiniteETA = 8640000 * time.Second
+
+type Torrent struct {
+	Hash              string       `json:"hash"`
+	InfohashV1        string       `json:"infohash_v1"`
+	InfohashV2        string       `json:"infohash_v2"`
+	Name              string       `json:"name"`
+	MagnetURI         string       `json:"magnet_uri"`
+	State             TorrentState `json:"state"`
+	Progress          float64      `json:"progress"`
+	Size              ByteSize     `json:"size"`
+	TotalSize         ByteSize     `json:"total_size"`
+	Downloaded        ByteSize     `json:"downloaded"`
+	Uploaded          ByteSize     `json:"uploaded"`
+	DownloadedSession ByteSize     `json:"downloaded_session"`
+	UploadedSession   ByteSize     `json:"uploaded_session"`
+	AmountLeft        ByteSize     `json:"amount_left"`
+	Completed         ByteSize     `json:"completed"`
+	DlSpeed           ByteRate     `json:"dlspeed"`
+	UpSpeed           ByteRate     `json:"upspeed"`
+	DlLimit           ByteRate     `json:"dl_limit"`
+	UpLimit           ByteRate     `json:"up_limit"`
+	Ratio             float64      `json:"ratio"`
+	RatioLimit        float64      `json:"ratio_limit"`
+	Availability      float64      `json:"availability"`
+	Category          string       `json:"category"`
+	Tags              []string     `json:"-"`
+	SavePath          string       `json:"save_path"`
+	DownloadPath      string       `json:"download_path"`
+	ContentPath       string       `json:"content_path"`
+	Tracker           string       `json:"tracker"`
+	Priority          int          `json:"priority"`
+	NumSeeds          int          `json:"num_seeds"`
+	NumComplete       int          `json:"num_complete"`
+	NumLeechs         int          `json:"num_leechs"`
+	NumIncomplete     int          `json:"num_incomplete"`
+	SequentialDL      bool         `json:"seq_dl"`
+	FirstLastPiece    bool         `json:"f_l_piece_prio"`
+	ForceStart        bool         `json:"force_start"`
+	SuperSeeding      bool         `json:"super_seeding"`
+	AutoTMM           bool         `json:"auto_tmm"`
+	AddedOn           time.Time    `json:"-"`
+	CompletionOn      time.Time    `json:"-"`
+	LastActivity      time.Time    `json:"-"`
+	SeenComplete      time.Time    `json:"-"`
+	// ETA is InfiniteETA when the torrent is not expected to finish.
+	ETA         time.Duration `json:"-"`
+	TimeActive  time.Duration `json:"-"`
+	SeedingTime time.Duration `json:"-"`
+}
+
+// UnmarshalJSON only overwrites the fields present in data, so a partial
+// torrent object from sync/maindata can be applied on top of a previous value.
+func (t *Torrent) UnmarshalJSON(data []byte) error {
+	type plain Torrent
+	aux := struct {
+		*plain
+		Tags         *string `json:"tags"`
+		AddedOn      *int64  `json:"added_on"`
+		CompletionOn *int64  `json:"completion_on"`
+		LastActivity *int64  `json:"last_activity"`
+		SeenComplete *int64  `json:"seen_complete"`
+		ETA          *int64  `json:"eta"`
+		TimeActive   *int64  `json:"time_active"`
+		SeedingTime  *int64  `json:"seeding_time"`
+		DlLimit      *int64  `json:"dl_limit"`
+		UpLimit      *int64  `json:"up_limit"`
+	}{plain: (*plain)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.Tags != nil {
+		t.Tags = splitTags(*aux.Tags)
+	}
+	if aux.AddedOn != nil {
+		t.AddedOn = unixTime(*aux.AddedOn)
+	}
+	if aux.CompletionOn != nil {
+		t.CompletionOn = unixTime(*aux.CompletionOn)
+	}
+	if aux.LastActivity != nil {
+		t.LastActivity = unixTime(*aux.LastActivity)
+	}
+	if aux.SeenComplete != nil {
+		t.SeenComplete = unixTime(*aux.SeenComplete)
+	}
+	if aux.ETA != nil {
+		t.ETA = time.Duration(*aux.ETA) * time.Second
+	}
+	if aux.TimeActive != nil {
+		t.TimeActive = time.Duration(*aux.TimeActive) * time.Second
+	}
+	if aux.SeedingTime != nil {
+		t.SeedingTime = time.Duration(*aux.SeedingTime) * time.Second
+	}
+	// Unlimited is -1 on the wire but 0 for ByteRate limits.
+	if aux.DlLimit != nil {
+		t.DlLimit = ByteRate(max(*aux.DlLimit, 0))
+	}
+	if aux.UpLimit != nil {
+		t.UpLimit = ByteRate(max(*aux.UpLimit, 0))
+	}
+	return nil
+}
+
+// MarshalJSON encodes t in the server's format, so that it decodes back to
+// the same value with UnmarshalJSON.
+func (t Torrent) MarshalJSON() ([]byte, error) {
+	type plain Torrent
+	return json.Marshal(struct {
+		plain
+		Tags         string `json:"tags"`
+		AddedOn      int64  `json:"added_on"`
+		CompletionOn int64  `json:"completion_on"`
+		LastActivity int64  `json:"last_activity"`
+		SeenComplete int64  `json:"seen_complete"`
+		ETA          int64  `json:"eta"`
+		TimeActive   int64  `json:"time_active"`
+		SeedingTime  int64  `json:"seeding_time"`
+	}{
+		plain:        plain(t),
+		Tags:         strings.Join(t.Tags, ", "),
+		AddedOn:      unixSeconds(t.AddedOn),
+		CompletionOn: unixSeconds(t.CompletionOn),
+		LastActivity: unixSeconds(t.LastActivity),
+		SeenComplete: unixSeconds(t.SeenComplete),
+		ETA:          int64(t.ETA / time.Second),
+		TimeActive:   int64(t.TimeActive / time.Second),
+		SeedingTime:  int64(t.SeedingTime / time.Second),
+	})
+}
+
+// unixTime converts a qBittorrent timestamp in seconds, treating zero and
+// negative sentinels as "never".
+func unixTime(sec int64) time.Time {
+	if sec <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// unixSeconds is the inverse of unixTime, encoding "never" as -1.
+func unixSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return -1
+	}
+	return t.Unix()
+}
+
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+type TorrentFilter string
+
+const (
+	FilterAll                TorrentFilter = "all"
+	FilterDownloading        TorrentFilter = "downloading"
+	FilterSeeding            TorrentFilter = "seeding"
+	FilterCompleted          TorrentFilter = "completed"
+	FilterPaused             TorrentFilter = "paused"
+	FilterStopped            TorrentFilter = "stopped"
+	FilterResumed            TorrentFilter = "resumed"
+	FilterRunning            TorrentFilter = "running"
+	FilterActive             TorrentFilter = "active"
+	FilterInactive           TorrentFilter = "inactive"
+	FilterStalled            TorrentFilter = "stalled"
+	FilterStalledUploading   TorrentFilter = "stalled_uploading"
+	FilterStalledDownloading TorrentFilter = "stalled_downloading"
+	FilterErrored            TorrentFilter = "errored"
+)
+
+// renamed reports whether the filter has a different name before Web API
+// 2.11.0.
+func (f TorrentFilter) renamed() bool {
+	switch f {
+	case FilterPaused, FilterStopped, FilterResumed, FilterRunning:
+		return true
+	}
+	return false
+}
+
+// forAPI translates paused/resumed and stopped/running to the names the
+// server understands, so either spelling works against any version.
+func (f TorrentFilter) forAPI(stopStart bool) TorrentFilter {
+	switch {
+	case stopStart && f == FilterPaused:
+		return FilterStopped
+	case stopStart && f == FilterResumed:
+		return FilterRunning
+	case !stopStart && f == FilterStopped:
+		return FilterPaused
+	case !stopStart && f == FilterRunning:
+		return FilterResumed
+	}
+	return f
+}
+
+// TorrentListOptions narrows down /api/v2/torrents/info. Category and Tag are
+// pointers because an empty string asks the server for torrents without a
+// category or tag.
+type TorrentListOptions struct {
+	Filter   TorrentFilter
+	Category *string
+	Tag      *string
+	Sort     string
+	Reverse  bool
+	Limit    int
+	Offset   int
+	Hashes   []string
+}
+
+func (o *TorrentListOptions) values() url.Values {
+	data := url.Values{}
+	if o == nil {
+		return data
+	}
+	if o.Filter != "" {
+		data.Set("filter", string(o.Filter))
+	}
+	if o.Category != nil {
+		data.Set("category", *o.Category)
+	}
+	if o.Tag != nil {
+		data.Set("tag", *o.Tag)
+	}
+	if o.Sort != "" {
+		data.Set("sort", o.Sort)
+	}
+	if o.Reverse {
+		data.Set("reverse", "true")
+	}
+	if o.Limit > 0 {
+		data.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset != 0 {
+		data.Set("offset", strconv.Itoa(o.Offset))
+	}
+	if len(o.Hashes) > 0 {
+		data.Set("hashes", strings.Join(o.Hashes, "|"))
+	}
+	return data
+}
@@ -0,0 +1,86 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetTorrentListOptions(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`[{"hash":"abc","name":"ubuntu.iso","state":"stalledUP","progress":1,
+			"size":1024,"ratio":2.5,"category":"linux","tags":"iso, seed","added_on":1700000000,
+			"completion_on":-1,"eta":8640000,"dl_limit":-1,"up_limit":1024}]`))
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	category := ""
+	torrents, err := client.GetTorrentList(&TorrentListOptions{
+		Filter:   FilterSeeding,
+		Category: &category,
+		Sort:     "ratio",
+		Reverse:  true,
+		Limit:    5,
+		Hashes:   []string{"abc", "def"},
+	})
+	if err != nil {
+		t.Fatalf("GetTorrentList failed: %v", err)
+	}
+
+	if want := "category=&filter=seeding&hashes=abc%7Cdef&limit=5&reverse=true&sort=ratio"; query != want {
+		t.Errorf("query = %q, want %q", query, want)
+	}
+
+	if len(torrents) != 1 {
+		t.Fatalf("expected 1 torrent, got %d", len(torrents))
+	}
+	torrent := torrents[0]
+	if torrent.State != StateStalledUP || torrent.Ratio != 2.5 || torrent.Size != 1024 {
+		t.Errorf("unexpected torrent: %+v", torrent)
+	}
+	if torrent.DlLimit != 0 || torrent.UpLimit != KiB || torrent.UpLimit.String() != "1.0 KiB/s" {
+		t.Errorf("unexpected limits: %v %v", torrent.DlLimit, torrent.UpLimit)
+	}
+	if len(torrent.Tags) != 2 || torrent.Tags[0] != "iso" || torrent.Tags[1] != "seed" {
+		t.Errorf("unexpected tags: %q", torrent.Tags)
+	}
+	if !torrent.AddedOn.Equal(time.Unix(1700000000, 0)) || !torrent.CompletionOn.IsZero() {
+		t.Errorf("unexpected timestamps: %v %v", torrent.AddedOn, torrent.CompletionOn)
+	}
+}
+
+func TestTorrentJSONRoundTrip(t *testing.T) {
+	want := Torrent{
+		Hash:        "abc",
+		Name:        "ubuntu.iso",
+		State:       StateDownloading,
+		Size:        1024,
+		Tags:        []string{"iso", "linux"},
+		AddedOn:     time.Unix(1700000000, 0),
+		ETA:         InfiniteETA,
+		TimeActive:  time.Hour,
+		SeedingTime: 0,
+	}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got Torrent
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,132 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type TrackerStatus int
+
+const (
+	TrackerDisabled     TrackerStatus = 0
+	TrackerNotContacted TrackerStatus = 1
+	TrackerWorking      TrackerStatus = 2
+	TrackerUpdating     TrackerStatus = 3
+	TrackerNotWorking   TrackerStatus = 4
+)
+
+func (s TrackerStatus) String() string {
+	switch s {
+	case TrackerDisabled:
+		return "disabled"
+	case TrackerNotContacted:
+		return "not contacted"
+	case TrackerWorking:
+		return "working"
+	case TrackerUpdating:
+		return "updating"
+	case TrackerNotWorking:
+		return "not working"
+	}
+	return "TrackerStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// Tracker is an entry of /api/v2/torrents/trackers. The list starts with
+// pseudo-trackers for DHT, PeX and LSD, which have a Tier of -1. The peer
+// counts are -1 when the tracker has not reported them.
+type Tracker struct {
+	URL           string        `json:"url"`
+	Status        TrackerStatus `json:"status"`
+	Tier          int           `json:"-"`
+	NumPeers      int           `json:"num_peers"`
+	NumSeeds      int           `json:"num_seeds"`
+	NumLeeches    int           `json:"num_leeches"`
+	NumDownloaded int           `json:"num_downloaded"`
+	Msg           string        `json:"msg"`
+}
+
+func (t *Tracker) UnmarshalJSON(data []byte) error {
+	type plain Tracker
+	aux := struct {
+		*plain
+		Tier json.RawMessage `json:"tier"`
+	}{plain: (*plain)(t)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// Older servers send an empty string as the tier of the pseudo-trackers.
+	t.Tier = -1
+	if tier := strings.Trim(string(aux.Tier), `"`); tier != "" {
+		n, err := strconv.Atoi(tier)
+		if err != nil {
+			return fmt.Errorf("invalid tracker tier %s: %w", aux.Tier, err)
+		}
+		t.Tier = n
+	}
+	return nil
+}
+
+// IsPseudo reports whether t is the DHT, PeX or LSD entry rather than a
+// real tracker.
+func (t Tracker) IsPseudo() bool {
+	return strings.HasPrefix(t.URL, "** [")
+}
+
+// TrackerReplacement is the outcome of replacing a tracker URL in one
+// torrent. Err is nil if the tracker was replaced.
+type TrackerReplacement struct {
+	Hash string
+	Name string
+	Err  error
+}
+
+// ReplaceTrackerURL replaces the tracker oldURL with newURL in every torrent
+// that has it and returns one TrackerReplacement per such torrent. Failures
+// on individual torrents are reported in the results; the returned error is
+// only set if the torrents could not be listed or ctx was cancelled.
+//
+// The torrent list only shows each torrent's current tracker, so torrents
+// whose current tracker is oldURL are edited directly, but every other
+// torrent costs an extra torrents/trackers request to check its full
+// tracker list. On large instances that is one request per torrent.
+func (q *QBittorrentClient) ReplaceTrackerURL(oldURL string, newURL string) ([]TrackerReplacement, error) {
+	return q.ReplaceTrackerURLContext(context.Background(), oldURL, newURL)
+}
+
+func (q *QBittorrentClient) ReplaceTrackerURLContext(ctx context.Context, oldURL string, newURL string) ([]TrackerReplacement, error) {
+	torrents, err := q.GetTorrentListContext(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []TrackerReplacement
+	for _, torrent := range torrents {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		if torrent.Tracker == oldURL {
+			err := q.EditTrackersContext(ctx, torrent.Hash, oldURL, newURL)
+			results = append(results, TrackerReplacement{Hash: torrent.Hash, Name: torrent.Name, Err: err})
+			continue
+		}
+		trackers, err := q.GetTorrentTrackersContext(ctx, torrent.Hash)
+		if err != nil {
+			results = append(results, TrackerReplacement{Hash: torrent.Hash, Name: torrent.Name, Err: err})
+			continue
+		}
+		for _, tracker := range trackers {
+			if tracker.URL == oldURL {
+				err := q.EditTrackersContext(ctx, torrent.Hash, oldURL, newURL)
+				results = append(results, TrackerReplacement{Hash: torrent.Hash, Name: torrent.Name, Err: err})
+				break
+			}
+		}
+	}
+	return results, ctx.Err()
+}
@@ -0,0 +1,91 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestTrackerTier(t *testing.T) {
+	var trackers []Tracker
+	err := json.Unmarshal([]byte(`[
+		{"url":"** [DHT] **","status":0,"tier":"","num_peers":12,"num_seeds":-1,"num_leeches":-1,"num_downloaded":-1,"msg":""},
+		{"url":"http://tracker.example/announce","status":4,"tier":1,"num_peers":-1,"msg":"timed out"}
+	]`), &trackers)
+	if err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !trackers[0].IsPseudo() || trackers[0].Tier != -1 || trackers[0].Status != TrackerDisabled {
+		t.Errorf("unexpected DHT entry: %+v", trackers[0])
+	}
+	if trackers[1].IsPseudo() || trackers[1].Tier != 1 || trackers[1].Status != TrackerNotWorking {
+		t.Errorf("unexpected tracker: %+v", trackers[1])
+	}
+	if got := trackers[1].Status.String(); got != "not working" {
+		t.Errorf("Status.String() = %q", got)
+	}
+}
+
+func TestEditTrackers(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "abc", Name: "a", Trackers: []qbittorrenttest.Tracker{{URL: "http://old.example/announce"}}})
+
+	client := newTestClient(t, server)
+
+	// The server looks the tracker up by the origUrl form field.
+	if err := client.EditTrackers("abc", "http://old.example/announce", "http://new.example/announce"); err != nil {
+		t.Fatalf("EditTrackers failed: %v", err)
+	}
+	if torrent, _ := server.Torrent("abc"); torrent.Trackers[0].URL != "http://new.example/announce" {
+		t.Errorf("unexpected trackers: %+v", torrent.Trackers)
+	}
+	if err := client.EditTrackers("abc", "http://missing.example/announce", "http://other.example/announce"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for an unknown tracker, got %v", err)
+	}
+}
+
+func TestReplaceTrackerURL(t *testing.T) {
+	const oldURL, newURL = "http://old.example/announce", "https://new.example/announce"
+
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "aaa", Name: "a", Tracker: oldURL, Trackers: []qbittorrenttest.Tracker{{URL: oldURL, Status: 2}}})
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "bbb", Name: "b", Trackers: []qbittorrenttest.Tracker{{URL: "http://other.example/announce"}}})
+	// Already has the new URL, so the server refuses the edit.
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "ccc", Name: "c", Trackers: []qbittorrenttest.Tracker{{URL: oldURL}, {URL: newURL}}})
+
+	client := newTestClient(t, server)
+
+	results, err := client.ReplaceTrackerURL(oldURL, newURL)
+	if err != nil {
+		t.Fatalf("ReplaceTrackerURL failed: %v", err)
+	}
+	byHash := map[string]error{}
+	for _, result := range results {
+		byHash[result.Hash] = result.Err
+	}
+	if len(byHash) != 2 {
+		t.Fatalf("expected results for aaa and ccc, got %+v", results)
+	}
+	// aaa's current tracker is oldURL, so its tracker list is not fetched.
+	if n := server.Requests("/api/v2/torrents/trackers"); n != 2 {
+		t.Errorf("expected 2 tracker lookups, got %d", n)
+	}
+	if err := byHash["aaa"]; err != nil {
+		t.Errorf("replacing in aaa failed: %v", err)
+	}
+	if err := byHash["ccc"]; !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for ccc, got %v", err)
+	}
+
+	trackers, err := client.GetTorrentTrackers("aaa")
+	if err != nil {
+		t.Fatalf("GetTorrentTrackers failed: %v", err)
+	}
+	last := trackers[len(trackers)-1]
+	if last.URL != newURL || last.Tier != 0 || last.Status != TrackerWorking {
+		t.Errorf("unexpected tracker after replacement: %+v", last)
+	}
+}
@@ -0,0 +1,77 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+type ConnectionStatus string
+
+const (
+	ConnectionConnected    ConnectionStatus = "connected"
+	ConnectionFirewalled   ConnectionStatus = "firewalled"
+	ConnectionDisconnected ConnectionStatus = "disconnected"
+)
+
+// Binary size units, usable with both ByteSize and ByteRate.
+const (
+	KiB = 1 << 10
+	MiB = 1 << 20
+	GiB = 1 << 30
+	TiB = 1 << 40
+)
+
+// ByteSize is an amount of data in bytes.
+type ByteSize int64
+
+// String formats the size with binary units, e.g. "1.5 GiB".
+func (b ByteSize) String() string {
+	return formatBytes(int64(b))
+}
+
+// ByteRate is a transfer rate or rate limit in bytes per second. For limits,
+// zero means unlimited.
+type ByteRate int64
+
+// String formats the rate with binary units, e.g. "512.0 KiB/s".
+func (r ByteRate) String() string {
+	return formatBytes(int64(r)) + "/s"
+}
+
+func formatBytes(n int64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	value := float64(n)
+	unit := 0
+	for (value >= 1024 || value <= -1024) && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64) + " " + units[unit]
+}
+
+// TransferInfo is the global transfer state returned by /api/v2/transfer/info.
+type TransferInfo struct {
+	ConnectionStatus ConnectionStatus `json:"connection_status"`
+	DHTNodes         int              `json:"dht_nodes"`
+	DlInfoData       ByteSize         `json:"dl_info_data"`
+	DlInfoSpeed      ByteRate         `json:"dl_info_speed"`
+	DlRateLimit      ByteRate         `json:"dl_rate_limit"`
+	UpInfoData       ByteSize         `json:"up_info_data"`
+	UpInfoSpeed      ByteRate         `json:"up_info_speed"`
+	UpRateLimit      ByteRate         `json:"up_rate_limit"`
+}
+
+// parseLooseFloat decodes a number that the API may send either as a JSON
+// number or as a string, as it does for global_ratio and the cache
+// statistics.
+func parseLooseFloat(raw json.RawMessage) (float64, error) {
+	s := strings.Trim(string(raw), `"`)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
@@ -0,0 +1,199 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestTransferInfo(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{
+		Hash:       "0123456789abcdef0123456789abcdef01234567",
+		DlSpeed:    3 * MiB,
+		Downloaded: 2 * GiB,
+		Uploaded:   3 * GiB,
+	})
+
+	client := newTestClient(t, server)
+
+	info, err := client.GetGlobalTransferInfo()
+	if err != nil {
+		t.Fatalf("GetGlobalTransferInfo failed: %v", err)
+	}
+	if info.ConnectionStatus != ConnectionConnected || info.DlInfoSpeed != 3*MiB || info.DlInfoData != 2*GiB {
+		t.Errorf("unexpected transfer info: %+v", info)
+	}
+
+	syncer := NewSyncer(client)
+	if err := syncer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	state := syncer.ServerState()
+	if state.TransferInfo != *info {
+		t.Errorf("server state transfer info = %+v, want %+v", state.TransferInfo, *info)
+	}
+	if state.GlobalRatio != 1.5 || state.AlltimeUl != 3*GiB || state.FreeSpaceOnDisk == 0 {
+		t.Errorf("unexpected server state: %+v", state)
+	}
+
+	// Partial updates leave the other fields alone.
+	if err := json.Unmarshal([]byte(`{"global_ratio":"2.25","read_cache_hits":"12.5"}`), &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.GlobalRatio != 2.25 || state.ReadCacheHits != 12.5 || state.DlInfoSpeed != 3*MiB {
+		t.Errorf("partial update was not merged: %+v", state)
+	}
+}
+
+func TestByteUnits(t *testing.T) {
+	for _, tt := range []struct {
+		got, want string
+	}{
+		{ByteSize(512).String(), "512 B"},
+		{ByteSize(1536 * MiB).String(), "1.5 GiB"},
+		{ByteRate(0).String(), "0 B/s"},
+		{ByteRate(512 * KiB).String(), "512.0 KiB/s"},
+		{ByteRate(5 * MiB).String(), "5.0 MiB/s"},
+	} {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestSetAlternativeSpeedLimits(t *testing.T) {
+	for _, tt := range []struct{ app, api string }{
+		{"v4.6.7", "2.9.3"},
+		{"v5.0.2", "2.11.2"},
+		{"v5.1.0", "2.11.3"},
+	} {
+		t.Run(tt.app, func(t *testing.T) {
+			server := qbittorrenttest.NewServer()
+			server.SetVersion(tt.app, tt.api)
+
+			client := newTestClient(t, server)
+
+			for _, enabled := range []bool{true, true, false, false, true} {
+				var wg sync.WaitGroup
+				for i := 0; i < 2; i++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						if err := client.SetAlternativeSpeedLimits(enabled); err != nil {
+							t.Errorf("SetAlternativeSpeedLimits(%t) failed: %v", enabled, err)
+						}
+					}()
+				}
+				wg.Wait()
+
+				state, err := client.GetAlternativeSpeedLimitsState()
+				if err != nil {
+					t.Fatalf("GetAlternativeSpeedLimitsState failed: %v", err)
+				}
+				if state != enabled {
+					t.Fatalf("alternative speed limits = %t, want %t", state, enabled)
+				}
+			}
+		})
+	}
+}
+
+// TestSetAlternativeSpeedLimitsRouting checks that the endpoint is chosen
+// from the server version alone. The server answers setSpeedLimitsMode on
+// every version, so a client that probed it first would be caught below the
+// boundary.
+func TestSetAlternativeSpeedLimitsRouting(t *testing.T) {
+	for _, tt := range []struct {
+		api  string
+		want []string
+	}{
+		{"2.11.2", []string{"/api/v2/transfer/speedLimitsMode", "/api/v2/transfer/toggleSpeedLimitsMode"}},
+		{"2.11.3", []string{"/api/v2/transfer/setSpeedLimitsMode"}},
+	} {
+		t.Run(tt.api, func(t *testing.T) {
+			var paths []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v2/app/webapiVersion":
+					w.Write([]byte(tt.api))
+				case "/api/v2/app/version":
+					w.Write([]byte("v5.1.0"))
+				case "/api/v2/app/buildInfo":
+					w.Write([]byte(`{"libtorrent":"2.0.11.0"}`))
+				case "/api/v2/transfer/speedLimitsMode":
+					paths = append(paths, r.URL.Path)
+					w.Write([]byte("0"))
+				default:
+					paths = append(paths, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			client, err := NewDefaultClient(server.URL)
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+			if err := client.SetAlternativeSpeedLimits(true); err != nil {
+				t.Fatalf("SetAlternativeSpeedLimits failed: %v", err)
+			}
+			if !slices.Equal(paths, tt.want) {
+				t.Errorf("requests = %v, want %v", paths, tt.want)
+			}
+		})
+	}
+}
+
+func TestBanPeers(t *testing.T) {
+	const banPeers = "/api/v2/transfer/banPeers"
+	server := qbittorrenttest.NewServer()
+	client := newTestClient(t, server)
+
+	peers := []netip.AddrPort{netip.MustParseAddrPort("[2001:db8::1]:51413")}
+	for i := 0; i < 1200; i++ {
+		addr := netip.AddrFrom4([4]byte{10, 0, byte(i >> 8), byte(i)})
+		peers = append(peers, netip.AddrPortFrom(addr, 6881))
+	}
+	peers = append(peers, netip.MustParseAddrPort("[::ffff:192.0.2.1]:6881"))
+	if err := client.BanPeers(peers); err != nil {
+		t.Fatalf("BanPeers failed: %v", err)
+	}
+	if n := server.Requests(banPeers); n != 3 {
+		t.Errorf("expected 3 batches, got %d requests", n)
+	}
+	if banned := server.BannedPeers(); len(banned) != len(peers) || banned[0] != "[2001:db8::1]:51413" {
+		t.Errorf("server banned %d peers, first %q", len(banned), banned[0])
+	}
+
+	ips, err := client.GetBannedIPs()
+	if err != nil {
+		t.Fatalf("GetBannedIPs failed: %v", err)
+	}
+	if len(ips) != len(peers) || ips[0] != peers[0].Addr() || ips[1200] != peers[1200].Addr() {
+		t.Errorf("unexpected banned IPs: %d", len(ips))
+	}
+	// IPv4-mapped peers are banned, and reported, as plain IPv4.
+	if last := ips[len(ips)-1]; last != netip.MustParseAddr("192.0.2.1") {
+		t.Errorf("IPv4-mapped peer banned as %v", last)
+	}
+
+	invalid := []netip.AddrPort{
+		netip.MustParseAddrPort("192.0.2.1:6881"),
+		netip.AddrPortFrom(netip.MustParseAddr("192.0.2.2"), 0),
+	}
+	if err := client.BanPeers(invalid); err == nil {
+		t.Error("expected an error for a peer without a port")
+	}
+	if err := client.BanPeers([]netip.AddrPort{{}}); err == nil {
+		t.Error("expected an error for an invalid peer")
+	}
+	if n := server.Requests(banPeers); n != 3 {
+		t.Errorf("invalid input should not be sent, got %d requests in total", n)
+	}
+}
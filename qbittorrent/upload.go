@@ -0,0 +1,59 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+)
+
+// TorrentUpload is a .torrent file sent as a "torrents" part by AddTorrents.
+type TorrentUpload struct {
+	Filename string
+	Reader   io.Reader
+
+	path string
+}
+
+// TorrentFromFile reads the .torrent file at path when the request is built.
+func TorrentFromFile(path string) TorrentUpload {
+	return TorrentUpload{Filename: filepath.Base(path), path: path}
+}
+
+// TorrentFromBytes uploads data as a .torrent file. filename is only sent as
+// the name of the multipart part; qBittorrent does not use it for the
+// torrent's name.
+func TorrentFromBytes(filename string, data []byte) TorrentUpload {
+	return TorrentUpload{Filename: filename, Reader: bytes.NewReader(data)}
+}
+
+// TorrentFromReader uploads the contents of r as a .torrent file, with
+// filename used as for TorrentFromBytes. r is read to the end once, when
+// AddTorrents builds the request, so the upload cannot be reused afterwards.
+func TorrentFromReader(filename string, r io.Reader) TorrentUpload {
+	return TorrentUpload{Filename: filename, Reader: r}
+}
+
+func (t TorrentUpload) writeTo(w *multipart.Writer) error {
+	r := t.Reader
+	if t.path != "" {
+		f, err := os.Open(t.path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	if r == nil {
+		return fmt.Errorf("torrent upload %q has no data", t.Filename)
+	}
+
+	part, err := w.CreateFormFile("torrents", t.Filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, r)
+	return err
+}
@@ -0,0 +1,156 @@
+package qbittorrent
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddTorrentsMultipart(t *testing.T) {
+	type part struct{ filename, content string }
+	var (
+		fields map[string][]string
+		files  []part
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.11.2"))
+			return
+		case "/api/v2/app/version":
+			w.Write([]byte("v5.0.2"))
+			return
+		case "/api/v2/app/buildInfo":
+			w.Write([]byte(`{"libtorrent":"2.0.10.0"}`))
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("request is not multipart: %v", err)
+			return
+		}
+		fields = r.MultipartForm.Value
+		files = nil
+		for _, header := range r.MultipartForm.File["torrents"] {
+			f, _ := header.Open()
+			data, _ := io.ReadAll(f)
+			f.Close()
+			files = append(files, part{header.Filename, string(data)})
+		}
+		if len(files) > 0 && files[0].content == "broken" {
+			w.Write([]byte("Fails."))
+			return
+		}
+		w.Write([]byte("Ok."))
+	}))
+	defer server.Close()
+
+	client, err := NewDefaultClient(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "disk.torrent")
+	if err := os.WriteFile(path, []byte("from disk"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.AddTorrents(
+		[]string{"magnet:?xt=urn:btih:aaa", "https://example.com/b.torrent"},
+		[]TorrentUpload{
+			TorrentFromFile(path),
+			TorrentFromBytes("memory.torrent", []byte("from memory")),
+			TorrentFromReader("reader.torrent", strings.NewReader("from reader")),
+		},
+		&AddTorrentOptions{Category: "linux", Stopped: Ptr(true), RatioLimit: Ptr(1.5)},
+	)
+	if err != nil {
+		t.Fatalf("AddTorrents failed: %v", err)
+	}
+
+	if got := fields["urls"]; len(got) != 1 || got[0] != "magnet:?xt=urn:btih:aaa\nhttps://example.com/b.torrent" {
+		t.Errorf("urls = %q", got)
+	}
+	for key, value := range map[string]string{
+		"category":   "linux",
+		"stopped":    "true",
+		"ratioLimit": "1.5",
+	} {
+		if got := fields[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if _, ok := fields["savepath"]; ok {
+		t.Errorf("unset options should not be sent: %v", fields)
+	}
+	if _, ok := fields["paused"]; ok {
+		t.Errorf("paused should not be sent to a 5.x server: %v", fields)
+	}
+	want := []part{
+		{"disk.torrent", "from disk"},
+		{"memory.torrent", "from memory"},
+		{"reader.torrent", "from reader"},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("got %d torrent parts, want %d", len(files), len(want))
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("part %d = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+
+	err = client.AddTorrents(nil, []TorrentUpload{TorrentFromBytes("bad.torrent", []byte("broken"))}, nil)
+	if !errors.Is(err, ErrAddTorrentFailed) {
+		t.Errorf("expected ErrAddTorrentFailed, got %v", err)
+	}
+}
+
+func TestAddTorrentLayout(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		api     Version
+		options AddTorrentOptions
+		key     string
+		want    string
+		err     error
+	}{
+		{"root folder on new server", Version{2, 7, 0}, AddTorrentOptions{RootFolder: Ptr(true)}, "contentLayout", "Subfolder", nil},
+		{"no root folder on new server", Version{2, 7, 0}, AddTorrentOptions{RootFolder: Ptr(false)}, "contentLayout", "NoSubfolder", nil},
+		{"layout on new server", Version{2, 7, 0}, AddTorrentOptions{ContentLayout: ContentLayoutOriginal}, "contentLayout", "Original", nil},
+		{"matching options on new server", Version{2, 7, 0}, AddTorrentOptions{RootFolder: Ptr(true), ContentLayout: ContentLayoutSubfolder}, "contentLayout", "Subfolder", nil},
+		{"conflict on new server", Version{2, 7, 0}, AddTorrentOptions{RootFolder: Ptr(true), ContentLayout: ContentLayoutNoSubfolder}, "", "", ErrUnsupported},
+		{"root folder on old server", Version{2, 6, 2}, AddTorrentOptions{RootFolder: Ptr(true)}, "root_folder", "true", nil},
+		{"subfolder on old server", Version{2, 6, 2}, AddTorrentOptions{ContentLayout: ContentLayoutSubfolder}, "root_folder", "true", nil},
+		{"no subfolder on old server", Version{2, 6, 2}, AddTorrentOptions{ContentLayout: ContentLayoutNoSubfolder}, "root_folder", "false", nil},
+		{"original on old server", Version{2, 6, 2}, AddTorrentOptions{ContentLayout: ContentLayoutOriginal}, "", "", ErrUnsupported},
+		{"conflict on old server", Version{2, 6, 2}, AddTorrentOptions{RootFolder: Ptr(false), ContentLayout: ContentLayoutSubfolder}, "", "", ErrUnsupported},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.options.values(tt.api)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := data.Get(tt.key); got != tt.want {
+				t.Errorf("%s = %q, want %q", tt.key, got, tt.want)
+			}
+			other := "root_folder"
+			if tt.key == other {
+				other = "contentLayout"
+			}
+			if data.Has(other) {
+				t.Errorf("%s should not be sent: %v", other, data)
+			}
+		})
+	}
+}
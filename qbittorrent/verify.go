@@ -0,0 +1,223 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// VerifyResult is the outcome of checking local data against a torrent's
+// piece hashes.
+type VerifyResult struct {
+	// Pieces is the number of pieces checked.
+	Pieces int
+	// BadPieces lists the pieces whose data is missing or does not match
+	// the expected hash, in order.
+	BadPieces []int
+	// BadFiles lists the files that overlap at least one bad piece.
+	BadFiles []TorrentFile
+	// MissingFiles lists the files that do not exist locally. Their pieces
+	// are also in BadPieces.
+	MissingFiles []TorrentFile
+}
+
+func (r *VerifyResult) OK() bool {
+	return len(r.BadPieces) == 0
+}
+
+// VerifyTorrentData checks the torrent's content in dir against the piece
+// hashes reported by the server. dir takes the place of the torrent's save
+// path, so file names are resolved relative to it.
+func (q *QBittorrentClient) VerifyTorrentData(hash string, dir string) (*VerifyResult, error) {
+	return q.VerifyTorrentDataContext(context.Background(), hash, dir)
+}
+
+func (q *QBittorrentClient) VerifyTorrentDataContext(ctx context.Context, hash string, dir string) (*VerifyResult, error) {
+	properties, err := q.GetTorrentGenericPropertiesContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	files, err := q.GetTorrentContentsContext(ctx, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := q.GetTorrentPiecesHashesContext(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return VerifyLocalData(ctx, dir, files, properties.PieceSize, hashes)
+}
+
+// fileSegment is where a file's data lives in the torrent's piece space.
+type fileSegment struct {
+	file   TorrentFile
+	offset int64
+}
+
+// VerifyLocalData hashes the files under dir piece by piece and compares
+// them with pieceHashes, the hex SHA-1 hashes returned by
+// GetTorrentPiecesHashes. Only v1 piece hashes are supported.
+//
+// Files are laid out in index order. The pad files of hybrid torrents are
+// not part of the file list, so a file whose PieceRange starts after the
+// end of the previous file is aligned to its first piece, with zeros in
+// between.
+func VerifyLocalData(ctx context.Context, dir string, files []TorrentFile, pieceSize ByteSize, pieceHashes []string) (*VerifyResult, error) {
+	if pieceSize <= 0 {
+		return nil, fmt.Errorf("invalid piece size %d", pieceSize)
+	}
+	expected := make([][]byte, len(pieceHashes))
+	for i, h := range pieceHashes {
+		sum, err := hex.DecodeString(h)
+		if err != nil || len(sum) != sha1.Size {
+			return nil, fmt.Errorf("%w: piece hash %q is not a SHA-1 hash", ErrUnsupported, h)
+		}
+		expected[i] = sum
+	}
+
+	sorted := append([]TorrentFile(nil), files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	size := int64(pieceSize)
+	var segments []fileSegment
+	var end int64
+	for _, file := range sorted {
+		if file.Size == 0 {
+			continue
+		}
+		offset := end
+		if first := int64(file.PieceRange[0]); offset/size < first {
+			offset = first * size
+		}
+		segments = append(segments, fileSegment{file: file, offset: offset})
+		end = offset + int64(file.Size)
+	}
+
+	v := &verifier{
+		dir:      dir,
+		segments: segments,
+		open:     make(map[int]*os.File),
+		missing:  make(map[int]bool),
+	}
+	defer v.close()
+
+	result := &VerifyResult{Pieces: len(expected)}
+	badFiles := make(map[int]bool)
+	buf := make([]byte, size)
+	for piece, want := range expected {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		start := int64(piece) * size
+		length := min(size, end-start)
+		if length <= 0 {
+			result.BadPieces = append(result.BadPieces, piece)
+			continue
+		}
+		ok, overlapping, err := v.readPiece(buf[:length], start)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sum := sha1.Sum(buf[:length])
+			ok = bytes.Equal(sum[:], want)
+		}
+		if !ok {
+			result.BadPieces = append(result.BadPieces, piece)
+			for _, i := range overlapping {
+				badFiles[i] = true
+			}
+		}
+	}
+
+	for i, segment := range segments {
+		if badFiles[i] {
+			result.BadFiles = append(result.BadFiles, segment.file)
+		}
+		if v.missing[i] {
+			result.MissingFiles = append(result.MissingFiles, segment.file)
+		}
+	}
+	return result, nil
+}
+
+type verifier struct {
+	dir      string
+	segments []fileSegment
+	open     map[int]*os.File
+	missing  map[int]bool
+}
+
+// readPiece fills buf with the data at offset. It reports false if part of
+// the data is missing, along with the indexes of the segments the piece
+// overlaps. Gaps between segments read as zeros.
+func (v *verifier) readPiece(buf []byte, offset int64) (bool, []int, error) {
+	clear(buf)
+	ok := true
+	var overlapping []int
+	// Segments are ordered by offset, so skip those that end before the piece.
+	first := sort.Search(len(v.segments), func(i int) bool {
+		return v.segments[i].offset+int64(v.segments[i].file.Size) > offset
+	})
+	for i := first; i < len(v.segments) && v.segments[i].offset < offset+int64(len(buf)); i++ {
+		segment := v.segments[i]
+		segmentEnd := segment.offset + int64(segment.file.Size)
+		overlapping = append(overlapping, i)
+
+		from := max(segment.offset, offset)
+		to := min(segmentEnd, offset+int64(len(buf)))
+		f, err := v.file(i)
+		if err != nil {
+			return false, nil, err
+		}
+		if f == nil {
+			ok = false
+			continue
+		}
+		if _, err := f.ReadAt(buf[from-offset:to-offset], from-segment.offset); err != nil {
+			if !errors.Is(err, io.EOF) {
+				return false, nil, err
+			}
+			ok = false
+		}
+		// Later pieces start after this one, so a file that ends here is
+		// not needed again.
+		if segmentEnd <= offset+int64(len(buf)) {
+			f.Close()
+			delete(v.open, i)
+		}
+	}
+	return ok, overlapping, nil
+}
+
+// file returns the open file of segment i, or nil if it does not exist.
+func (v *verifier) file(i int) (*os.File, error) {
+	if f, ok := v.open[i]; ok || v.missing[i] {
+		return f, nil
+	}
+	f, err := os.Open(filepath.Join(v.dir, filepath.FromSlash(v.segments[i].file.Name)))
+	if errors.Is(err, fs.ErrNotExist) {
+		v.missing[i] = true
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	v.open[i] = f
+	return f, nil
+}
+
+func (v *verifier) close() {
+	for _, f := range v.open {
+		f.Close()
+	}
+}
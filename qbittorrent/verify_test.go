@@ -0,0 +1,135 @@
+package qbittorrent
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+// writeTorrentData writes files under dir and returns the piece hashes of
+// data, their concatenation in the torrent's piece space.
+func writeTorrentData(t *testing.T, dir string, contents map[string][]byte, data []byte, pieceSize int) []string {
+	t.Helper()
+	for name, content := range contents {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var hashes []string
+	for start := 0; start < len(data); start += pieceSize {
+		sum := sha1.Sum(data[start:min(start+pieceSize, len(data))])
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+	}
+	return hashes
+}
+
+func filled(n int, b byte) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = b + byte(i%7)
+	}
+	return data
+}
+
+func TestVerifyLocalData(t *testing.T) {
+	dir := t.TempDir()
+	a, b, c := filled(10, 'a'), filled(9, 'b'), filled(5, 'c')
+	files := []TorrentFile{
+		{Index: 0, Name: "album/a.flac", Size: 10, PieceRange: [2]int{0, 2}},
+		{Index: 1, Name: "album/b.flac", Size: 9, PieceRange: [2]int{2, 4}},
+		{Index: 2, Name: "album/cover.jpg", Size: 5, PieceRange: [2]int{4, 5}},
+	}
+	data := append(append(append([]byte(nil), a...), b...), c...)
+	hashes := writeTorrentData(t, dir, map[string][]byte{"album/a.flac": a, "album/b.flac": b}, data, 4)
+
+	// Corrupt a byte in piece 1, which only belongs to a.flac.
+	a[5] ^= 0xff
+	if err := os.WriteFile(filepath.Join(dir, "album", "a.flac"), a, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := VerifyLocalData(context.Background(), dir, files, 4, hashes)
+	if err != nil {
+		t.Fatalf("VerifyLocalData failed: %v", err)
+	}
+	if result.OK() || result.Pieces != 6 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if want := []int{1, 4, 5}; !reflect.DeepEqual(result.BadPieces, want) {
+		t.Errorf("BadPieces = %v, want %v", result.BadPieces, want)
+	}
+	if want := []TorrentFile{files[0], files[1], files[2]}; !reflect.DeepEqual(result.BadFiles, want) {
+		t.Errorf("BadFiles = %+v, want %+v", result.BadFiles, want)
+	}
+	if want := []TorrentFile{files[2]}; !reflect.DeepEqual(result.MissingFiles, want) {
+		t.Errorf("MissingFiles = %+v, want %+v", result.MissingFiles, want)
+	}
+}
+
+func TestVerifyLocalDataPadding(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filled(6, 'a'), filled(3, 'b')
+	// As in a hybrid torrent, b.bin starts on a piece boundary after a
+	// hidden pad file.
+	files := []TorrentFile{
+		{Index: 0, Name: "a.bin", Size: 6, PieceRange: [2]int{0, 1}},
+		{Index: 1, Name: "b.bin", Size: 3, PieceRange: [2]int{2, 2}},
+	}
+	data := append(append(append([]byte(nil), a...), 0, 0), b...)
+	hashes := writeTorrentData(t, dir, map[string][]byte{"a.bin": a, "b.bin": b}, data, 4)
+
+	result, err := VerifyLocalData(context.Background(), dir, files, 4, hashes)
+	if err != nil {
+		t.Fatalf("VerifyLocalData failed: %v", err)
+	}
+	if !result.OK() {
+		t.Errorf("expected the data to verify, got %+v", result)
+	}
+}
+
+func TestVerifyTorrentData(t *testing.T) {
+	dir := t.TempDir()
+	content := filled(20, 'x')
+	hashes := writeTorrentData(t, dir, map[string][]byte{"single.iso": content}, content, 8)
+
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{
+		Hash:        "abc",
+		Name:        "single.iso",
+		PieceSize:   8,
+		PieceHashes: hashes,
+		Files:       []qbittorrenttest.File{{Index: 0, Name: "single.iso", Size: 20, PieceRange: [2]int{0, 2}}},
+	})
+
+	client := newTestClient(t, server)
+
+	result, err := client.VerifyTorrentData("abc", dir)
+	if err != nil {
+		t.Fatalf("VerifyTorrentData failed: %v", err)
+	}
+	if !result.OK() || result.Pieces != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if err := os.Truncate(filepath.Join(dir, "single.iso"), 18); err != nil {
+		t.Fatal(err)
+	}
+	result, err = client.VerifyTorrentData("abc", dir)
+	if err != nil {
+		t.Fatalf("VerifyTorrentData failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.BadPieces, []int{2}) || len(result.BadFiles) != 1 || len(result.MissingFiles) != 0 {
+		t.Errorf("expected the truncated last piece to fail, got %+v", result)
+	}
+}
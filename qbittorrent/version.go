@@ -0,0 +1,178 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version such as the Web API version "2.11.0" or the
+// application version "v5.0.0".
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses versions like "2.9.3", "v4.6.7" or "5.0.0beta1".
+// Missing components are zero and pre-release suffixes are ignored.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if trimmed == "" {
+		return v, fmt.Errorf("invalid version %q", s)
+	}
+
+	parts := strings.SplitN(trimmed, ".", 3)
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(part[:end])
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", s)
+		}
+		*fields[i] = n
+		if end < len(part) {
+			break
+		}
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal to
+// or greater than other.
+func (v Version) Compare(other Version) int {
+	for _, d := range [...]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) AtLeast(major, minor, patch int) bool {
+	return v.Compare(Version{major, minor, patch}) >= 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Web API versions that introduced features the client adapts to.
+var (
+	// apiBuildInfo added /api/v2/app/buildInfo.
+	apiBuildInfo = Version{2, 3, 0}
+	// apiContentLayout replaced the root_folder add option with
+	// contentLayout (qBittorrent 4.3.2).
+	apiContentLayout = Version{2, 7, 0}
+	// apiRenamePaths added renameFolder and switched renameFile to
+	// oldPath/newPath.
+	apiRenamePaths = Version{2, 7, 0}
+	// apiStopStart renamed pause/resume to stop/start, the "paused" add
+	// option to "stopped" and the paused/resumed filters to
+	// stopped/running (qBittorrent 5.0.0).
+	apiStopStart = Version{2, 11, 0}
+	// apiSpeedLimitsMode added /api/v2/transfer/setSpeedLimitsMode
+	// (qBittorrent 5.1.0).
+	apiSpeedLimitsMode = Version{2, 11, 3}
+	// apiSendTestEmail added /api/v2/app/sendTestEmail (qBittorrent 5.1.0).
+	apiSendTestEmail = Version{2, 11, 4}
+	// apiWebSeeds added addWebSeeds, editWebSeed and removeWebSeeds
+	// (qBittorrent 5.1.0).
+	apiWebSeeds = Version{2, 11, 3}
+)
+
+// ServerVersion describes the server the client is talking to.
+// Libtorrent is zero when the server is too old to report build info.
+type ServerVersion struct {
+	App        Version
+	API        Version
+	Libtorrent Version
+}
+
+// ServerVersion returns the version of the server, querying it on first use.
+// The result is cached until the next login, since a new session may belong
+// to an upgraded daemon.
+func (q *QBittorrentClient) ServerVersion() (ServerVersion, error) {
+	return q.ServerVersionContext(context.Background())
+}
+
+func (q *QBittorrentClient) ServerVersionContext(ctx context.Context) (ServerVersion, error) {
+	if version := q.cachedVersion(); version != nil {
+		return *version, nil
+	}
+
+	q.versionMu.Lock()
+	defer q.versionMu.Unlock()
+	if version := q.cachedVersion(); version != nil {
+		return *version, nil
+	}
+
+	var version ServerVersion
+	api, err := q.GetAPIVersionContext(ctx)
+	if err != nil {
+		return version, err
+	}
+	if version.API, err = ParseVersion(api); err != nil {
+		return version, err
+	}
+
+	app, err := q.GetApplicationVersionContext(ctx)
+	if err != nil {
+		return version, err
+	}
+	if version.App, err = ParseVersion(app); err != nil {
+		return version, err
+	}
+
+	if version.API.Compare(apiBuildInfo) >= 0 {
+		info, err := q.getBuildInfo(ctx)
+		if err != nil {
+			return version, err
+		}
+		if info.Libtorrent != "" {
+			if version.Libtorrent, err = ParseVersion(info.Libtorrent); err != nil {
+				return version, err
+			}
+		}
+	}
+
+	q.mu.Lock()
+	q.version = &version
+	q.mu.Unlock()
+	return version, nil
+}
+
+func (q *QBittorrentClient) cachedVersion() *ServerVersion {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.version
+}
+
+// requireAPI returns ErrUnsupported if the server's Web API is older than
+// min.
+func (q *QBittorrentClient) requireAPI(ctx context.Context, min Version, feature string) error {
+	version, err := q.ServerVersionContext(ctx)
+	if err != nil {
+		return err
+	}
+	if version.API.Compare(min) < 0 {
+		return fmt.Errorf("%w: %s requires Web API %s, server has %s", ErrUnsupported, feature, min, version.API)
+	}
+	return nil
+}
+
+// hasStopStart reports whether the server uses the qBittorrent 5 names for
+// pausing and resuming torrents.
+func (q *QBittorrentClient) hasStopStart(ctx context.Context) (bool, error) {
+	version, err := q.ServerVersionContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version.API.Compare(apiStopStart) >= 0, nil
+}
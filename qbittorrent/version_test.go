@@ -0,0 +1,147 @@
+package qbittorrent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestParseVersion(t *testing.T) {
+	for input, want := range map[string]Version{
+		"2.9.3":      {2, 9, 3},
+		"v4.6.7":     {4, 6, 7},
+		"5.0.0beta1": {5, 0, 0},
+		"2.11":       {2, 11, 0},
+		"1.2.3.4":    {1, 2, 3},
+	} {
+		got, err := ParseVersion(input)
+		if err != nil || got != want {
+			t.Errorf("ParseVersion(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "v", "beta", "2..1"} {
+		if _, err := ParseVersion(input); err == nil {
+			t.Errorf("ParseVersion(%q) should fail", input)
+		}
+	}
+
+	if !(Version{2, 11, 0}).AtLeast(2, 9, 3) || (Version{2, 9, 3}).AtLeast(2, 11, 0) {
+		t.Error("AtLeast compares components numerically")
+	}
+}
+
+func TestVersionRouting(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	for _, tt := range []struct {
+		app, api string
+		stopped  bool
+	}{
+		{"v4.6.7", "2.9.3", false},
+		{"v5.0.2", "2.11.2", true},
+	} {
+		t.Run(tt.app, func(t *testing.T) {
+			server := qbittorrenttest.NewServer()
+			server.SetVersion(tt.app, tt.api)
+
+			client := newTestClient(t, server)
+
+			version, err := client.ServerVersion()
+			if err != nil {
+				t.Fatalf("ServerVersion failed: %v", err)
+			}
+			if version.API.String() != tt.api || "v"+version.App.String() != tt.app || version.Libtorrent.Major == 0 {
+				t.Errorf("unexpected version: %+v", version)
+			}
+
+			err = client.AddNewTorrent([]string{"magnet:?xt=urn:btih:" + hash}, &AddTorrentOptions{Stopped: Ptr(true)})
+			if err != nil {
+				t.Fatalf("AddNewTorrent failed: %v", err)
+			}
+			stopped, err := client.GetTorrentList(&TorrentListOptions{Filter: FilterPaused})
+			if err != nil {
+				t.Fatalf("GetTorrentList failed: %v", err)
+			}
+			if len(stopped) != 1 || !stopped[0].State.IsStopped() {
+				t.Fatalf("expected the torrent to be added stopped, got %+v", stopped)
+			}
+
+			if err := client.ResumeTorrents([]string{hash}); err != nil {
+				t.Fatalf("ResumeTorrents failed: %v", err)
+			}
+			running, err := client.GetTorrentList(&TorrentListOptions{Filter: FilterRunning})
+			if err != nil {
+				t.Fatalf("GetTorrentList failed: %v", err)
+			}
+			if len(running) != 1 {
+				t.Fatalf("expected the torrent to be running, got %+v", running)
+			}
+			if err := client.PauseTorrents([]string{hash}); err != nil {
+				t.Fatalf("PauseTorrents failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnsupportedFeature(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.SetVersion("v4.1.9", "2.2.1")
+
+	client := newTestClient(t, server)
+
+	if _, err := client.GetBuildInfo(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported for buildInfo on Web API 2.2.1, got %v", err)
+	}
+
+	err := client.RenameFolder("0123456789abcdef0123456789abcdef01234567", "old", "new")
+	if !errors.Is(err, ErrUnsupported) || !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
+
+// TestFeatureVersions calls each version-gated method at the first Web API
+// version that has it and at the one before. Below the boundary the client
+// must refuse on its own, without the fake's 404 turning into ErrNotFound.
+func TestFeatureVersions(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+
+	for _, tt := range []struct {
+		name      string
+		below, at string
+		call      func(*QBittorrentClient) error
+	}{
+		{"SendTestEmail", "2.11.3", "2.11.4", func(client *QBittorrentClient) error {
+			return client.SendTestEmail()
+		}},
+		{"AddWebSeeds", "2.11.2", "2.11.3", func(client *QBittorrentClient) error {
+			return client.AddWebSeeds(hash, []string{"http://b.example/ubuntu.iso"})
+		}},
+		{"EditWebSeed", "2.11.2", "2.11.3", func(client *QBittorrentClient) error {
+			return client.EditWebSeed(hash, "http://a.example/ubuntu.iso", "https://a.example/ubuntu.iso")
+		}},
+		{"RemoveWebSeeds", "2.11.2", "2.11.3", func(client *QBittorrentClient) error {
+			return client.RemoveWebSeeds(hash, []string{"http://a.example/ubuntu.iso"})
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := qbittorrenttest.NewServer()
+			server.SetVersion("v5.1.0", tt.below)
+			server.AddTorrent(qbittorrenttest.Torrent{Hash: hash, WebSeeds: []string{"http://a.example/ubuntu.iso"}})
+			client := newTestClient(t, server)
+
+			if err := tt.call(client); !errors.Is(err, ErrUnsupported) || errors.Is(err, ErrNotFound) {
+				t.Errorf("expected ErrUnsupported on Web API %s, got %v", tt.below, err)
+			}
+
+			server.SetVersion("v5.1.0", tt.at)
+			// The server version is cached until the next login.
+			if err := client.Login(testUsername, testPassword); err != nil {
+				t.Fatalf("Login failed: %v", err)
+			}
+			if err := tt.call(client); err != nil {
+				t.Errorf("expected success on Web API %s, got %v", tt.at, err)
+			}
+		})
+	}
+}
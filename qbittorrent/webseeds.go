@@ -0,0 +1,99 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WebSeed is an HTTP source (BEP 19) a torrent downloads from in addition
+// to its peers.
+type WebSeed struct {
+	URL string `json:"url"`
+}
+
+func (q *QBittorrentClient) AddWebSeeds(hash string, urls []string) error {
+	return q.AddWebSeedsContext(context.Background(), hash, urls)
+}
+
+func (q *QBittorrentClient) AddWebSeedsContext(ctx context.Context, hash string, urls []string) error {
+	if err := q.requireAPI(ctx, apiWebSeeds, "addWebSeeds"); err != nil {
+		return err
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("urls", strings.Join(urls, "|"))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/addWebSeeds", strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (q *QBittorrentClient) EditWebSeed(hash string, origURL string, newURL string) error {
+	return q.EditWebSeedContext(context.Background(), hash, origURL, newURL)
+}
+
+func (q *QBittorrentClient) EditWebSeedContext(ctx context.Context, hash string, origURL string, newURL string) error {
+	if err := q.requireAPI(ctx, apiWebSeeds, "editWebSeed"); err != nil {
+		return err
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("origUrl", origURL)
+	data.Set("newUrl", newURL)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/editWebSeed", strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+func (q *QBittorrentClient) RemoveWebSeeds(hash string, urls []string) error {
+	return q.RemoveWebSeedsContext(context.Background(), hash, urls)
+}
+
+func (q *QBittorrentClient) RemoveWebSeedsContext(ctx context.Context, hash string, urls []string) error {
+	if err := q.requireAPI(ctx, apiWebSeeds, "removeWebSeeds"); err != nil {
+		return err
+	}
+
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("urls", strings.Join(urls, "|"))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", q.baseURL+"/api/v2/torrents/removeWebSeeds", strings.NewReader(data.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := q.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package qbittorrent
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/guchengod/go-qbittorrent-api/qbittorrent/qbittorrenttest"
+)
+
+func TestWebSeeds(t *testing.T) {
+	server := qbittorrenttest.NewServer()
+	server.AddTorrent(qbittorrenttest.Torrent{Hash: "abc", Name: "ubuntu.iso", WebSeeds: []string{"http://a.example/ubuntu.iso"}})
+
+	client := newTestClient(t, server)
+
+	if err := client.AddWebSeeds("abc", []string{"http://b.example/ubuntu.iso"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported from a 4.x server, got %v", err)
+	}
+	// The server version is cached until the next login.
+	server.SetVersion("v5.1.0", "2.11.4")
+	if err := client.Login(testUsername, testPassword); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if err := client.AddWebSeeds("abc", []string{"http://b.example/ubuntu.iso", "http://c.example/ubuntu.iso"}); err != nil {
+		t.Fatalf("AddWebSeeds failed: %v", err)
+	}
+	if err := client.EditWebSeed("abc", "http://a.example/ubuntu.iso", "https://a.example/ubuntu.iso"); err != nil {
+		t.Fatalf("EditWebSeed failed: %v", err)
+	}
+	if err := client.EditWebSeed("abc", "http://missing.example/", "https://a.example/"); !errors.Is(err, ErrConflict) {
+		t.Errorf("expected ErrConflict for an unknown web seed, got %v", err)
+	}
+	if err := client.AddWebSeeds("abc", []string{"not a url"}); !errors.Is(err, ErrBadRequest) {
+		t.Errorf("expected ErrBadRequest for an invalid URL, got %v", err)
+	}
+	if err := client.RemoveWebSeeds("abc", []string{"http://b.example/ubuntu.iso"}); err != nil {
+		t.Fatalf("RemoveWebSeeds failed: %v", err)
+	}
+
+	seeds, err := client.GetTorrentWebSeeds("abc")
+	if err != nil {
+		t.Fatalf("GetTorrentWebSeeds failed: %v", err)
+	}
+	want := []WebSeed{{URL: "https://a.example/ubuntu.iso"}, {URL: "http://c.example/ubuntu.iso"}}
+	if !reflect.DeepEqual(seeds, want) {
+		t.Errorf("web seeds = %+v, want %+v", seeds, want)
+	}
+}